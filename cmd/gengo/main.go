@@ -0,0 +1,98 @@
+// Command gengo generates a Go function evaluating a logical expression, so
+// it can be embedded without depending on this project.
+//
+// The expression is given with -expr, read from the standard input, or
+// loaded from the database at DATABASE_URL with -id:
+//
+//	gengo -expr "x AND (y OR NOT z)" -package rules -func Rule42 -o rule42.go
+//	DATABASE_URL=... gengo -id 42
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/db"
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+)
+
+func main() {
+	expression := flag.String("expr", "", "logical expression, in any dialect")
+	id := flag.Int64("id", 0, "ID of a stored expression, read from DATABASE_URL")
+	pkg := flag.String("package", "", `package of the generated file (default "rules")`)
+	function := flag.String("func", "", `name of the generated function (default "Rule", or "Rule<id>" with -id)`)
+	output := flag.String("o", "", "output file (default standard output)")
+	flag.Parse()
+
+	opts := logic.GoOptions{Package: *pkg, Function: *function}
+
+	var (
+		code []byte
+		err  error
+	)
+	if *id != 0 {
+		code, err = generateFromDatabase(*id, opts)
+	} else {
+		code, err = generateFromText(*expression, opts)
+	}
+	if err != nil {
+		log.Fatalf("error generating Go code: %s", err.Error())
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(code)
+	} else {
+		err = os.WriteFile(*output, code, 0o644)
+	}
+	if err != nil {
+		log.Fatalf("error writing Go code: %s", err.Error())
+	}
+}
+
+func generateFromText(expression string, opts logic.GoOptions) ([]byte, error) {
+	if expression == "" {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		expression = string(input)
+	}
+
+	node, err := logic.ParseWithDialect(expression, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return logic.ToGo(node, opts)
+}
+
+func generateFromDatabase(id int64, opts logic.GoOptions) ([]byte, error) {
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		return nil, errors.New("DATABASE_URL can't be empty")
+	}
+
+	conn, err := db.Open(dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the database: %w", err)
+	}
+	defer conn.Close()
+
+	expressionService := services.NewExpressionService(
+		services.WithExpressionRepositoryOption(repositories.NewRepository(repositories.WithDatabaseOption(conn))),
+	)
+
+	code, err := expressionService.GenerateGo(context.Background(), id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(code), nil
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/CaioTeixeira95/logic-exp/migrations"
 	"github.com/CaioTeixeira95/logic-exp/pkg/app"
@@ -36,21 +39,84 @@ func main() {
 	repository := repositories.NewRepository(repositories.WithDatabaseOption(conn))
 
 	// Services
-	expressionService := services.NewExpressionService(
+	expressionServiceOptions := []services.ExpressionServiceOption{
 		services.WithExpressionRepositoryOption(repository),
+		services.WithVariableRegistryOption(repository),
+	}
+	if os.Getenv("EVALUATOR") == "bdd" {
+		expressionServiceOptions = append(expressionServiceOptions, services.WithBDDEvaluationOption())
+	}
+
+	if os.Getenv("STRICT_EVALUATION") == "true" {
+		expressionServiceOptions = append(expressionServiceOptions, services.WithStrictEvaluationOption())
+	}
+
+	if value := os.Getenv("MATCH_INDEX_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			log.Fatalf("MATCH_INDEX_TTL must be a non-negative duration, got %q", value)
+		}
+		expressionServiceOptions = append(expressionServiceOptions, services.WithMatchIndexTTLOption(ttl))
+	}
+
+	limits, err := limitsFromEnv()
+	if err != nil {
+		log.Fatalf("error reading the expression limits: %s", err.Error())
+	}
+	expressionServiceOptions = append(expressionServiceOptions, services.WithLimitsOption(limits))
+
+	expressionService := services.NewExpressionService(expressionServiceOptions...)
+	variableService := services.NewVariableService(
+		services.WithVariableRepositoryOption(repository),
 	)
 
 	// Handlers
 	expressionHandler := handlers.NewExpressionHandler(
 		handlers.WithExpressionServiceOption(expressionService),
+		handlers.WithAdminTokenOption(os.Getenv("ADMIN_TOKEN")),
+	)
+
+	variableHandler := handlers.NewVariableHandler(
+		handlers.WithVariableServiceOption(variableService),
 	)
 
 	s := app.NewServer(
 		r,
 		app.WithExpressionHandlerOption(expressionHandler),
+		app.WithVariableHandlerOption(variableHandler),
 	)
 
 	if err := s.Run(); err != nil {
 		log.Fatalf("error running server: %s", err.Error())
 	}
 }
+
+// limitsFromEnv overrides the default expression limits with the ones set in
+// the environment. Zero disables a limit.
+func limitsFromEnv() (services.Limits, error) {
+	limits := services.DefaultLimits
+
+	vars := map[string]*int{
+		"EXPRESSION_MAX_LENGTH":     &limits.MaxLength,
+		"EXPRESSION_MAX_DEPTH":      &limits.MaxDepth,
+		"EXPRESSION_MAX_NODES":      &limits.MaxNodes,
+		"EXPRESSION_MAX_VARIABLES":  &limits.MaxVariables,
+		"EVALUATION_MAX_STEPS":      &limits.MaxEvaluationSteps,
+		"EVALUATION_MAX_BATCH_SIZE": &limits.MaxBatchSize,
+		"DIAGRAM_MAX_NODES":         &limits.MaxDiagramNodes,
+	}
+	for name, limit := range vars {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return limits, fmt.Errorf("%s must be a non-negative integer, got %q", name, value)
+		}
+		*limit = parsed
+	}
+
+	return limits, nil
+}
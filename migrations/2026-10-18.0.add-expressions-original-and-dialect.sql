@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE public.expressions
+    ADD COLUMN original_expression text NOT NULL DEFAULT '',
+    ADD COLUMN dialect text NOT NULL DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE public.expressions
+    DROP COLUMN original_expression,
+    DROP COLUMN dialect;
@@ -0,0 +1,15 @@
+-- +migrate Up
+
+CREATE TABLE public.variables (
+    name text PRIMARY KEY,
+    type text NOT NULL,
+    min_value integer,
+    max_value integer,
+    allowed_values integer[] NOT NULL DEFAULT '{}',
+    default_value integer,
+    description text NOT NULL DEFAULT ''
+);
+
+-- +migrate Down
+
+DROP TABLE public.variables;
@@ -0,0 +1,20 @@
+-- +migrate Up
+
+CREATE TABLE public.expression_variables (
+    expression_id integer NOT NULL REFERENCES public.expressions (id) ON DELETE CASCADE,
+    variable text NOT NULL,
+    PRIMARY KEY (expression_id, variable)
+);
+
+CREATE INDEX expression_variables_variable_idx ON public.expression_variables (variable);
+
+-- Variables are the lowercase words of the expressions, as extracted by
+-- GetLogicalExpressionParameters.
+INSERT INTO public.expression_variables (expression_id, variable)
+SELECT DISTINCT id, (regexp_matches(expression, '[a-z]+', 'g'))[1]
+FROM public.expressions
+WHERE expression IS NOT NULL;
+
+-- +migrate Down
+
+DROP TABLE public.expression_variables;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE public.expressions
+    ADD COLUMN namespace text NOT NULL DEFAULT '';
+
+CREATE INDEX expressions_namespace_idx ON public.expressions (namespace);
+
+-- +migrate Down
+
+ALTER TABLE public.expressions
+    DROP COLUMN namespace;
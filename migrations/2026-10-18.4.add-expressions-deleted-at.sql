@@ -0,0 +1,9 @@
+-- +migrate Up
+
+ALTER TABLE public.expressions
+    ADD COLUMN deleted_at timestamptz;
+
+-- +migrate Down
+
+ALTER TABLE public.expressions
+    DROP COLUMN deleted_at;
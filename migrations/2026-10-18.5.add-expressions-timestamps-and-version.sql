@@ -0,0 +1,13 @@
+-- +migrate Up
+
+ALTER TABLE public.expressions
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN version integer NOT NULL DEFAULT 1;
+
+-- +migrate Down
+
+ALTER TABLE public.expressions
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN version;
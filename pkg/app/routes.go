@@ -16,9 +16,37 @@ func (s *Server) Routes() *gin.Engine {
 		expGroup := r.Group("/expressions")
 		expGroup.POST("/", s.expressionHandler.CreateExpression)
 		expGroup.GET("/", s.expressionHandler.ListExpressions)
+		expGroup.POST("/format", s.expressionHandler.FormatExpression)
+		expGroup.POST("/synthesize", s.expressionHandler.SynthesizeExpression)
+		expGroup.GET("/:id", s.expressionHandler.GetExpression)
 		expGroup.PUT("/:id", s.expressionHandler.UpdateExpression)
+		expGroup.DELETE("/:id", s.expressionHandler.DeleteExpression)
+		expGroup.POST("/:id/restore", s.expressionHandler.RestoreExpression)
+		expGroup.GET("/:id/sql", s.expressionHandler.GetExpressionSQL)
+		expGroup.GET("/:id/graph", s.expressionHandler.GetExpressionGraph)
+		expGroup.GET("/:id/netlist", s.expressionHandler.GetExpressionNetlist)
+		expGroup.GET("/:id/go", s.expressionHandler.GetExpressionGo)
+		expGroup.GET("/:id/equivalent/:other_id", s.expressionHandler.CheckEquivalence)
+		expGroup.GET("/:id/analysis", s.expressionHandler.AnalyzeExpression)
+		expGroup.GET("/:id/models", s.expressionHandler.ListModels)
+		expGroup.GET("/:id/mcdc", s.expressionHandler.GenerateMCDC)
+		expGroup.GET("/:id/kmap", s.expressionHandler.GetKarnaughMap)
 
+		r.POST("/evaluate", s.expressionHandler.MatchExpressions)
 		r.GET("/evaluate/:id", s.expressionHandler.EvaluateExpression)
+		r.POST("/evaluate/:id/batch", s.expressionHandler.EvaluateBatch)
+
+		r.GET("/variables/:name/expressions", s.expressionHandler.ListVariableExpressions)
+		r.POST("/variables/:name/rename", s.expressionHandler.RenameVariable)
+	}
+
+	if s.variableHandler != nil {
+		varGroup := r.Group("/variables")
+		varGroup.POST("/", s.variableHandler.CreateVariable)
+		varGroup.GET("/", s.variableHandler.ListVariables)
+		varGroup.GET("/:name", s.variableHandler.GetVariable)
+		varGroup.PUT("/:name", s.variableHandler.UpdateVariable)
+		varGroup.DELETE("/:name", s.variableHandler.DeleteVariable)
 	}
 
 	return r
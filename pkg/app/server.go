@@ -11,6 +11,7 @@ import (
 type Server struct {
 	router            *gin.Engine
 	expressionHandler *handlers.ExpressionHandler
+	variableHandler   *handlers.VariableHandler
 }
 
 type ServerHandlerOption func(s *Server)
@@ -41,3 +42,9 @@ func WithExpressionHandlerOption(h *handlers.ExpressionHandler) ServerHandlerOpt
 		s.expressionHandler = h
 	}
 }
+
+func WithVariableHandlerOption(h *handlers.VariableHandler) ServerHandlerOption {
+	return func(s *Server) {
+		s.variableHandler = h
+	}
+}
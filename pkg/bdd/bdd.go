@@ -0,0 +1,455 @@
+// Package bdd compiles logical expressions into reduced ordered binary
+// decision diagrams. Equivalent functions share the same Ref within a
+// Manager, which makes equivalence checks constant time.
+package bdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+)
+
+// ErrTooLarge is the error of a bounded Manager needing more nodes than its
+// limit, see Manager.Bound.
+var ErrTooLarge = errors.New("decision diagram too large")
+
+// checkInterval is the number of ITE recursions between two checks of the
+// context of a bounded Manager.
+const checkInterval = 1024
+
+// Ref identifies a node of a Manager.
+type Ref int32
+
+const (
+	False Ref = 0
+	True  Ref = 1
+)
+
+// node is a decision on the variable at level: low is followed when the
+// variable is false and high when it is true. Terminals have level -1.
+type node struct {
+	level     int
+	low, high Ref
+}
+
+type iteKey struct {
+	f, g, h Ref
+}
+
+// Manager owns a shared node table for a fixed variable order.
+type Manager struct {
+	vars   []string
+	levels map[string]int
+
+	nodes    []node
+	unique   map[node]Ref
+	iteCache map[iteKey]Ref
+
+	// maxNodes and ctx bound the work of the Manager, and err is why it
+	// stopped, see Bound.
+	maxNodes int
+	ctx      context.Context
+	calls    int
+	err      error
+}
+
+// New returns a Manager with the given variable order, from the root down.
+func New(order []string) *Manager {
+	m := &Manager{
+		levels:   make(map[string]int, len(order)),
+		nodes:    []node{{level: -1}, {level: -1}},
+		unique:   make(map[node]Ref),
+		iteCache: make(map[iteKey]Ref),
+	}
+
+	for _, name := range order {
+		m.addVariable(name)
+	}
+
+	return m
+}
+
+func (m *Manager) addVariable(name string) int {
+	if level, ok := m.levels[name]; ok {
+		return level
+	}
+
+	m.levels[name] = len(m.vars)
+	m.vars = append(m.vars, name)
+
+	return m.levels[name]
+}
+
+// Bound makes the Manager stop building nodes once it holds maxNodes of
+// them, zero meaning no limit, or once the context is done. The operations
+// then return meaningless results and Err tells why, so the callers bounding
+// a Manager must check it. A nil context is never done.
+func (m *Manager) Bound(ctx context.Context, maxNodes int) {
+	m.ctx = ctx
+	m.maxNodes = maxNodes
+}
+
+// Err returns ErrTooLarge or the error of the context when a bounded Manager
+// stopped building nodes, and nil otherwise.
+func (m *Manager) Err() error {
+	return m.err
+}
+
+// NodeCount returns the number of nodes of the Manager, terminals and nodes
+// no longer reachable from any function included.
+func (m *Manager) NodeCount() int {
+	return len(m.nodes)
+}
+
+// Clone returns a copy of the Manager, sharing no state with it. The copy is
+// unbounded, whatever the bounds of the Manager. A Manager isn't safe for
+// concurrent use, as every operation building nodes writes to its tables,
+// but Eval, Size, AnySat, Graph and the counting functions only read them. A
+// Manager shared between goroutines must only be read, and cloned by the
+// callers of the other operations.
+func (m *Manager) Clone() *Manager {
+	c := &Manager{
+		vars:     append([]string(nil), m.vars...),
+		levels:   make(map[string]int, len(m.levels)),
+		nodes:    append([]node(nil), m.nodes...),
+		unique:   make(map[node]Ref, len(m.unique)),
+		iteCache: make(map[iteKey]Ref, len(m.iteCache)),
+	}
+
+	for name, level := range m.levels {
+		c.levels[name] = level
+	}
+	for n, ref := range m.unique {
+		c.unique[n] = ref
+	}
+	for key, ref := range m.iteCache {
+		c.iteCache[key] = ref
+	}
+
+	return c
+}
+
+// Variables returns the variable order of the Manager.
+func (m *Manager) Variables() []string {
+	return m.vars
+}
+
+// Var returns the function of a single variable. Unknown variables are
+// appended at the bottom of the order.
+func (m *Manager) Var(name string) Ref {
+	return m.mk(m.addVariable(name), False, True)
+}
+
+// mk returns the node deciding on level, applying the reduction rules.
+func (m *Manager) mk(level int, low, high Ref) Ref {
+	if low == high {
+		return low
+	}
+
+	n := node{level: level, low: low, high: high}
+	if ref, ok := m.unique[n]; ok {
+		return ref
+	}
+
+	if m.err != nil {
+		return False
+	}
+	if m.maxNodes > 0 && len(m.nodes) >= m.maxNodes {
+		m.err = fmt.Errorf("%w: more than %d nodes", ErrTooLarge, m.maxNodes)
+		return False
+	}
+
+	ref := Ref(len(m.nodes))
+	m.nodes = append(m.nodes, n)
+	m.unique[n] = ref
+
+	return ref
+}
+
+// level returns the level of a node, terminals being below every variable.
+func (m *Manager) level(f Ref) int {
+	if f == False || f == True {
+		return len(m.vars)
+	}
+	return m.nodes[f].level
+}
+
+// cofactors returns f restricted to the variable at level being false and true.
+func (m *Manager) cofactors(f Ref, level int) (Ref, Ref) {
+	if m.level(f) != level {
+		return f, f
+	}
+	return m.nodes[f].low, m.nodes[f].high
+}
+
+// ITE returns "if f then g else h", the operation every other one is built on.
+func (m *Manager) ITE(f, g, h Ref) Ref {
+	switch {
+	case f == True:
+		return g
+	case f == False:
+		return h
+	case g == h:
+		return g
+	case g == True && h == False:
+		return f
+	}
+
+	key := iteKey{f, g, h}
+	if ref, ok := m.iteCache[key]; ok {
+		return ref
+	}
+
+	if m.err != nil {
+		return False
+	}
+	if m.ctx != nil {
+		m.calls++
+		if m.calls%checkInterval == 0 {
+			if err := m.ctx.Err(); err != nil {
+				m.err = err
+				return False
+			}
+		}
+	}
+
+	top := m.level(f)
+	if l := m.level(g); l < top {
+		top = l
+	}
+	if l := m.level(h); l < top {
+		top = l
+	}
+
+	f0, f1 := m.cofactors(f, top)
+	g0, g1 := m.cofactors(g, top)
+	h0, h1 := m.cofactors(h, top)
+
+	ref := m.mk(top, m.ITE(f0, g0, h0), m.ITE(f1, g1, h1))
+	if m.err != nil {
+		return False
+	}
+	m.iteCache[key] = ref
+
+	return ref
+}
+
+func (m *Manager) Not(f Ref) Ref {
+	return m.ITE(f, False, True)
+}
+
+func (m *Manager) And(f, g Ref) Ref {
+	return m.ITE(f, g, False)
+}
+
+func (m *Manager) Or(f, g Ref) Ref {
+	return m.ITE(f, True, g)
+}
+
+func (m *Manager) Xor(f, g Ref) Ref {
+	return m.ITE(f, m.Not(g), g)
+}
+
+// Restrict returns f with the variable fixed to value.
+func (m *Manager) Restrict(f Ref, name string, value bool) Ref {
+	level, ok := m.levels[name]
+	if !ok {
+		return f
+	}
+
+	cache := map[Ref]Ref{}
+
+	var restrict func(f Ref) Ref
+	restrict = func(f Ref) Ref {
+		if m.level(f) > level {
+			return f
+		}
+		if ref, ok := cache[f]; ok {
+			return ref
+		}
+
+		n := m.nodes[f]
+
+		var ref Ref
+		switch {
+		case n.level == level && value:
+			ref = n.high
+		case n.level == level:
+			ref = n.low
+		default:
+			ref = m.mk(n.level, restrict(n.low), restrict(n.high))
+		}
+		cache[f] = ref
+
+		return ref
+	}
+
+	return restrict(f)
+}
+
+// Exists returns f with the variables existentially quantified.
+func (m *Manager) Exists(f Ref, names ...string) Ref {
+	for _, name := range names {
+		f = m.Or(m.Restrict(f, name, false), m.Restrict(f, name, true))
+	}
+	return f
+}
+
+// Compile returns the function of the tree.
+func (m *Manager) Compile(n *logic.Node) Ref {
+	switch n.Kind {
+	case logic.KindVariable:
+		return m.Var(n.Name)
+	case logic.KindConstant:
+		if n.Value {
+			return True
+		}
+		return False
+	case logic.KindNot:
+		return m.Not(m.Compile(n.Operands[0]))
+	case logic.KindAnd:
+		f := True
+		for _, operand := range n.Operands {
+			f = m.And(f, m.Compile(operand))
+		}
+		return f
+	case logic.KindOr:
+		f := False
+		for _, operand := range n.Operands {
+			f = m.Or(f, m.Compile(operand))
+		}
+		return f
+	}
+
+	panic(fmt.Sprintf("bdd: unknown node kind %d", n.Kind))
+}
+
+// Eval follows the decisions of f for the given values. Absent variables are
+// considered false.
+func (m *Manager) Eval(f Ref, values map[string]bool) bool {
+	for f != False && f != True {
+		n := m.nodes[f]
+		if values[m.vars[n.level]] {
+			f = n.high
+		} else {
+			f = n.low
+		}
+	}
+	return f == True
+}
+
+// Size returns the number of nodes reachable from f, terminals included.
+func (m *Manager) Size(f Ref) int {
+	seen := map[Ref]struct{}{}
+
+	var visit func(f Ref)
+	visit = func(f Ref) {
+		if _, ok := seen[f]; ok {
+			return
+		}
+		seen[f] = struct{}{}
+		if f != False && f != True {
+			visit(m.nodes[f].low)
+			visit(m.nodes[f].high)
+		}
+	}
+	visit(f)
+
+	return len(seen)
+}
+
+// AnySat returns an assignment satisfying f over the variables it depends
+// on, or false when f is unsatisfiable.
+func (m *Manager) AnySat(f Ref) (map[string]bool, bool) {
+	if f == False {
+		return nil, false
+	}
+
+	assignment := map[string]bool{}
+	for f != True {
+		n := m.nodes[f]
+		if n.low != False {
+			assignment[m.vars[n.level]] = false
+			f = n.low
+		} else {
+			assignment[m.vars[n.level]] = true
+			f = n.high
+		}
+	}
+
+	return assignment, true
+}
+
+// Equivalent reports whether two trees compute the same function. When they
+// don't, it returns an assignment of their variables on which they differ.
+// The diagrams are bounded by the context and maxNodes, as Manager.Bound
+// does.
+func Equivalent(ctx context.Context, a, b *logic.Node, maxNodes int) (bool, map[string]bool, error) {
+	m := New(append(a.Variables(), b.Variables()...))
+	m.Bound(ctx, maxNodes)
+
+	fa, fb := m.Compile(a), m.Compile(b)
+	diff := m.Xor(fa, fb)
+	if err := m.Err(); err != nil {
+		return false, nil, err
+	}
+	if fa == fb {
+		return true, nil, nil
+	}
+
+	counterexample, _ := m.AnySat(diff)
+	for _, name := range m.Variables() {
+		if _, ok := counterexample[name]; !ok {
+			counterexample[name] = false
+		}
+	}
+
+	return false, counterexample, nil
+}
+
+// Graph returns the diagram of f, ready to be rendered. Low edges are dashed.
+// When values are given, every node is overlaid with the value of its
+// sub-function.
+func (m *Manager) Graph(f Ref, values map[string]bool) *logic.Graph {
+	g := &logic.Graph{}
+	ids := map[Ref]int{}
+
+	var visit func(f Ref) int
+	visit = func(f Ref) int {
+		if id, ok := ids[f]; ok {
+			return id
+		}
+
+		id := len(g.Labels)
+		ids[f] = id
+
+		label := "1"
+		switch f {
+		case False:
+			label = "0"
+		case True:
+		default:
+			label = m.vars[m.nodes[f].level]
+		}
+
+		g.Labels = append(g.Labels, label)
+		g.Results = append(g.Results, nil)
+		if values != nil {
+			result := m.Eval(f, values)
+			g.Results[id] = &result
+		}
+
+		if f != False && f != True {
+			n := m.nodes[f]
+			g.Edges = append(g.Edges, logic.GraphEdge{From: id, To: visit(n.low), Label: "0", Dashed: true})
+			g.Edges = append(g.Edges, logic.GraphEdge{From: id, To: visit(n.high), Label: "1"})
+		}
+
+		return id
+	}
+	visit(f)
+
+	return g
+}
@@ -0,0 +1,229 @@
+package bdd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, expression string) *logic.Node {
+	node, err := logic.Parse(expression)
+	require.NoError(t, err)
+
+	return node
+}
+
+func mustCompile(t *testing.T, node *logic.Node) (*Manager, Ref) {
+	m, f, err := Compile(context.Background(), node, 0)
+	require.NoError(t, err)
+
+	return m, f
+}
+
+// assignments returns every assignment of the variables.
+func assignments(vars []string) []map[string]bool {
+	result := make([]map[string]bool, 0, 1<<len(vars))
+	for i := 0; i < 1<<len(vars); i++ {
+		values := make(map[string]bool, len(vars))
+		for j, name := range vars {
+			values[name] = i&(1<<j) != 0
+		}
+		result = append(result, values)
+	}
+	return result
+}
+
+func TestManager_Compile(t *testing.T) {
+	expressions := []string{
+		"x",
+		"NOT x",
+		"TRUE",
+		"x AND FALSE",
+		"x AND y OR z",
+		"((x OR y) AND (z OR k) OR j)",
+		"(a AND b) OR (c AND d) OR (e AND f)",
+		"NOT (x AND NOT y) OR NOT z AND x",
+	}
+
+	for _, expression := range expressions {
+		node := mustParse(t, expression)
+		m, f := mustCompile(t, node)
+
+		for _, values := range assignments(node.Variables()) {
+			assert.Equal(t, node.Eval(values), m.Eval(f, values), "%s with %v", expression, values)
+		}
+	}
+}
+
+func TestManager_Canonical(t *testing.T) {
+	m := New([]string{"x", "y", "z"})
+
+	f := m.Compile(mustParse(t, "x AND (y OR z)"))
+	g := m.Compile(mustParse(t, "x AND y OR z AND x"))
+	h := m.Compile(mustParse(t, "NOT (NOT x OR NOT y AND NOT z)"))
+
+	assert.Equal(t, f, g)
+	assert.Equal(t, f, h)
+
+	assert.Equal(t, True, m.Compile(mustParse(t, "x OR NOT x")))
+	assert.Equal(t, False, m.Compile(mustParse(t, "x AND NOT x")))
+}
+
+func TestManager_Clone(t *testing.T) {
+	m, f := mustCompile(t, mustParse(t, "x AND (y OR z)"))
+	nodes := len(m.nodes)
+
+	c := m.Clone()
+	g := c.Exists(f, "y")
+	h := c.Var("w")
+
+	assert.Equal(t, c.Compile(mustParse(t, "x")), g)
+	assert.Equal(t, nodes, len(m.nodes), "the original manager is left untouched")
+	assert.Equal(t, []string{"x", "y", "z"}, m.Variables())
+	assert.Equal(t, []string{"x", "y", "z", "w"}, c.Variables())
+	assert.True(t, c.Eval(h, map[string]bool{"w": true}))
+
+	for _, values := range assignments([]string{"x", "y", "z"}) {
+		assert.Equal(t, m.Eval(f, values), c.Eval(f, values), "%v", values)
+	}
+}
+
+func TestManager_RestrictAndExists(t *testing.T) {
+	m := New([]string{"x", "y"})
+	f := m.Compile(mustParse(t, "x AND y"))
+
+	assert.Equal(t, m.Var("y"), m.Restrict(f, "x", true))
+	assert.Equal(t, False, m.Restrict(f, "x", false))
+	assert.Equal(t, f, m.Restrict(f, "unknown", true))
+
+	assert.Equal(t, m.Var("y"), m.Exists(f, "x"))
+	assert.Equal(t, True, m.Exists(f, "x", "y"))
+}
+
+func TestManager_AnySat(t *testing.T) {
+	m := New([]string{"x", "y"})
+
+	assignment, ok := m.AnySat(m.Compile(mustParse(t, "NOT x AND y")))
+	require.True(t, ok)
+	assert.Equal(t, map[string]bool{"x": false, "y": true}, assignment)
+
+	_, ok = m.AnySat(False)
+	assert.False(t, ok)
+}
+
+func TestManager_Size(t *testing.T) {
+	m := New([]string{"x", "y"})
+
+	assert.Equal(t, 1, m.Size(True))
+	assert.Equal(t, 4, m.Size(m.Compile(mustParse(t, "x AND y"))))
+}
+
+// blowup returns an expression whose diagram has about 2^pairs nodes under
+// both orderings, which put every x variable above every y variable.
+func blowup(pairs int) string {
+	xs := make([]string, 0, pairs)
+	terms := make([]string, 0, pairs)
+	for i := 0; i < pairs; i++ {
+		suffix := string([]byte{'a' + byte(i/26), 'a' + byte(i%26)})
+		xs = append(xs, "x"+suffix)
+		terms = append(terms, fmt.Sprintf("(x%s AND y%s)", suffix, suffix))
+	}
+
+	return fmt.Sprintf("((%s) AND FALSE) OR (%s AND FALSE) OR %s",
+		strings.Join(xs, " OR "), strings.Join(xs, " AND "), strings.Join(terms, " OR "))
+}
+
+func TestManager_Bound(t *testing.T) {
+	t.Run("stops at the node limit", func(t *testing.T) {
+		m := New(nil)
+		m.Bound(context.Background(), 100)
+
+		m.Compile(mustParse(t, blowup(10)))
+
+		assert.ErrorIs(t, m.Err(), ErrTooLarge)
+		assert.LessOrEqual(t, m.NodeCount(), 100)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		m := New(nil)
+		m.Bound(ctx, 0)
+
+		m.Compile(mustParse(t, blowup(12)))
+
+		assert.ErrorIs(t, m.Err(), context.Canceled)
+	})
+
+	t.Run("leaves the clones unbounded", func(t *testing.T) {
+		m := New(nil)
+		m.Bound(context.Background(), 3)
+
+		c := m.Clone()
+		c.Compile(mustParse(t, "x AND y OR z"))
+
+		assert.NoError(t, c.Err())
+	})
+}
+
+func TestCompile_Bounds(t *testing.T) {
+	node := mustParse(t, blowup(40))
+
+	_, _, err := Compile(context.Background(), node, 10000)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	m, f, err := Compile(context.Background(), mustParse(t, blowup(4)), 10000)
+	require.NoError(t, err)
+	assert.False(t, m.Eval(f, map[string]bool{"xaa": true}))
+	assert.True(t, m.Eval(f, map[string]bool{"xaa": true, "yaa": true}))
+}
+
+func TestEquivalent(t *testing.T) {
+	ctx := context.Background()
+
+	ok, counterexample, err := Equivalent(ctx, mustParse(t, "NOT (x OR y)"), mustParse(t, "NOT x AND NOT y"), 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, counterexample)
+
+	a, b := mustParse(t, "x OR y"), mustParse(t, "x AND y OR z")
+	ok, counterexample, err = Equivalent(ctx, a, b, 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Len(t, counterexample, 3)
+	assert.NotEqual(t, a.Eval(counterexample), b.Eval(counterexample))
+
+	_, _, err = Equivalent(ctx, mustParse(t, blowup(20)), mustParse(t, "xaa"), 1000)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestManager_Graph(t *testing.T) {
+	m := New([]string{"x", "y"})
+	f := m.Compile(mustParse(t, "x AND y"))
+
+	got, err := m.Graph(f, nil).Render(logic.GraphFormatMermaid)
+	require.NoError(t, err)
+
+	expect := `graph TD
+  n0["x"]
+  n1["0"]
+  n2["y"]
+  n3["1"]
+  n0 -.->|0| n1
+  n2 -.->|0| n1
+  n2 -->|1| n3
+  n0 -->|1| n2
+`
+	assert.Equal(t, expect, got)
+
+	got, err = m.Graph(f, map[string]bool{"x": true, "y": true}).Render(logic.GraphFormatMermaid)
+	require.NoError(t, err)
+
+	assert.Contains(t, got, "class n0,n2,n3 true\n  class n1 false\n")
+}
@@ -0,0 +1,108 @@
+package bdd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidProbability = errors.New("invalid probability")
+
+// SatCount returns the number of assignments of the Manager's variables
+// satisfying f.
+func (m *Manager) SatCount(f Ref) *big.Int {
+	cache := map[Ref]*big.Int{}
+
+	// count returns the models of f over the variables from its level down.
+	var count func(f Ref) *big.Int
+	count = func(f Ref) *big.Int {
+		switch f {
+		case False:
+			return big.NewInt(0)
+		case True:
+			return big.NewInt(1)
+		}
+		if c, ok := cache[f]; ok {
+			return c
+		}
+
+		n := m.nodes[f]
+		low := new(big.Int).Lsh(count(n.low), uint(m.level(n.low)-n.level-1))
+		high := new(big.Int).Lsh(count(n.high), uint(m.level(n.high)-n.level-1))
+
+		c := low.Add(low, high)
+		cache[f] = c
+
+		return c
+	}
+
+	return new(big.Int).Lsh(count(f), uint(m.level(f)))
+}
+
+// Probability returns the probability of f being true when every variable is
+// independently true with the given probability. Every variable f depends
+// on must have a probability between 0 and 1.
+func (m *Manager) Probability(f Ref, probabilities map[string]float64) (float64, error) {
+	missing := []string{}
+	for name := range m.Support(f) {
+		p, ok := probabilities[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		// Written so that NaN is rejected as well.
+		if !(p >= 0 && p <= 1) {
+			return 0, fmt.Errorf("%w: %q must be between 0 and 1", ErrInvalidProbability, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return 0, fmt.Errorf("%w: missing probability of %s", ErrInvalidProbability, strings.Join(missing, ", "))
+	}
+
+	cache := map[Ref]float64{False: 0, True: 1}
+
+	var probability func(f Ref) float64
+	probability = func(f Ref) float64 {
+		if p, ok := cache[f]; ok {
+			return p
+		}
+
+		n := m.nodes[f]
+		p := probabilities[m.vars[n.level]]
+
+		result := (1-p)*probability(n.low) + p*probability(n.high)
+		cache[f] = result
+
+		return result
+	}
+
+	return probability(f), nil
+}
+
+// Support returns the variables f depends on.
+func (m *Manager) Support(f Ref) map[string]struct{} {
+	support := map[string]struct{}{}
+	seen := map[Ref]struct{}{}
+
+	var visit func(f Ref)
+	visit = func(f Ref) {
+		if f == False || f == True {
+			return
+		}
+		if _, ok := seen[f]; ok {
+			return
+		}
+		seen[f] = struct{}{}
+
+		n := m.nodes[f]
+		support[m.vars[n.level]] = struct{}{}
+		visit(n.low)
+		visit(n.high)
+	}
+	visit(f)
+
+	return support
+}
@@ -0,0 +1,80 @@
+package bdd
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_SatCount(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expect     int64
+	}{
+		{expression: "x", expect: 1},
+		{expression: "x OR y", expect: 3},
+		{expression: "x AND y AND z", expect: 1},
+		{expression: "x OR NOT x", expect: 2},
+		{expression: "x AND NOT x", expect: 0},
+		{expression: "(x OR y) AND z OR NOT k", expect: 11},
+		{expression: "a OR b OR c OR d", expect: 15},
+	}
+
+	for _, tc := range testCases {
+		node := mustParse(t, tc.expression)
+		m, f := mustCompile(t, node)
+
+		// Brute force over the variables of the expression.
+		var expect int64
+		for _, values := range assignments(node.Variables()) {
+			if node.Eval(values) {
+				expect++
+			}
+		}
+		require.Equal(t, tc.expect, expect, tc.expression)
+
+		assert.Equal(t, big.NewInt(tc.expect), m.SatCount(f), tc.expression)
+	}
+
+	// Variables f doesn't depend on double the count.
+	m := New([]string{"a", "x", "b"})
+	assert.Equal(t, big.NewInt(4), m.SatCount(m.Var("x")))
+	assert.Equal(t, big.NewInt(8), m.SatCount(True))
+}
+
+func TestManager_Probability(t *testing.T) {
+	m := New([]string{"x", "y"})
+
+	f := m.Compile(mustParse(t, "x AND y"))
+	p, err := m.Probability(f, map[string]float64{"x": 0.5, "y": 0.2})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, p, 1e-9)
+
+	f = m.Compile(mustParse(t, "x OR y"))
+	p, err = m.Probability(f, map[string]float64{"x": 0.5, "y": 0.2})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.6, p, 1e-9)
+
+	p, err = m.Probability(True, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, p)
+
+	_, err = m.Probability(f, map[string]float64{"x": 0.5})
+	assert.EqualError(t, err, "invalid probability: missing probability of y")
+
+	_, err = m.Probability(f, map[string]float64{"x": 0.5, "y": 1.5})
+	assert.EqualError(t, err, `invalid probability: "y" must be between 0 and 1`)
+
+	_, err = m.Probability(f, map[string]float64{"x": math.NaN(), "y": 0.5})
+	assert.EqualError(t, err, `invalid probability: "x" must be between 0 and 1`)
+}
+
+func TestManager_Support(t *testing.T) {
+	m := New([]string{"x", "y", "z"})
+	f := m.Compile(mustParse(t, "x AND y OR x AND NOT y OR z AND NOT z"))
+
+	assert.Equal(t, map[string]struct{}{"x": {}}, m.Support(f))
+}
@@ -0,0 +1,124 @@
+package bdd
+
+import "strings"
+
+// MCDC is a modified condition/decision coverage test set. Every pair shows
+// its variable independently affecting the outcome: the two vectors differ
+// only in that variable and f evaluates differently on them.
+type MCDC struct {
+	Vectors []map[string]bool
+	Pairs   []MCDCPair
+	// Uncoverable lists the variables that can't affect the outcome on their
+	// own, such as y in "x OR x AND y".
+	Uncoverable []string
+}
+
+// MCDCPair references the two vectors covering a variable, by index.
+type MCDCPair struct {
+	Variable    string
+	FalseVector int
+	TrueVector  int
+}
+
+// MCDC builds a small test set covering every variable of vars. Vectors are
+// reused greedily between variables, so n variables usually need n+1 vectors.
+func (m *Manager) MCDC(f Ref, vars []string) *MCDC {
+	g := &mcdcGenerator{m: m, f: f, vars: vars, index: map[string]int{}}
+	result := &MCDC{Vectors: []map[string]bool{}, Pairs: []MCDCPair{}, Uncoverable: []string{}}
+
+	for _, name := range vars {
+		derivative := m.Xor(m.Restrict(f, name, false), m.Restrict(f, name, true))
+		if derivative == False {
+			result.Uncoverable = append(result.Uncoverable, name)
+			continue
+		}
+
+		result.Pairs = append(result.Pairs, g.cover(name, derivative))
+	}
+	result.Vectors = g.vectors
+
+	return result
+}
+
+type mcdcGenerator struct {
+	m       *Manager
+	f       Ref
+	vars    []string
+	vectors []map[string]bool
+	// index finds vectors by their key.
+	index map[string]int
+}
+
+func (g *mcdcGenerator) key(vector map[string]bool) string {
+	var sb strings.Builder
+	for _, name := range g.vars {
+		if vector[name] {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+func (g *mcdcGenerator) add(vector map[string]bool) int {
+	key := g.key(vector)
+	if i, ok := g.index[key]; ok {
+		return i
+	}
+
+	g.index[key] = len(g.vectors)
+	g.vectors = append(g.vectors, vector)
+
+	return g.index[key]
+}
+
+func flip(vector map[string]bool, name string) map[string]bool {
+	flipped := make(map[string]bool, len(vector))
+	for k, v := range vector {
+		flipped[k] = v
+	}
+	flipped[name] = !flipped[name]
+	return flipped
+}
+
+// cover returns a pair for the variable, preferring pairs made of existing
+// vectors, then pairs needing a single new vector.
+func (g *mcdcGenerator) cover(name string, derivative Ref) MCDCPair {
+	pair := func(a, b int) MCDCPair {
+		if g.vectors[a][name] {
+			a, b = b, a
+		}
+		return MCDCPair{Variable: name, FalseVector: a, TrueVector: b}
+	}
+
+	var candidate = -1
+	for i, vector := range g.vectors {
+		if !g.m.Eval(derivative, vector) {
+			continue
+		}
+		if j, ok := g.index[g.key(flip(vector, name))]; ok {
+			return pair(i, j)
+		}
+		if candidate < 0 {
+			candidate = i
+		}
+	}
+
+	if candidate >= 0 {
+		return pair(candidate, g.add(flip(g.vectors[candidate], name)))
+	}
+
+	assignment, _ := g.m.AnySat(derivative)
+
+	vector := make(map[string]bool, len(g.vars))
+	for _, v := range g.vars {
+		vector[v] = assignment[v]
+	}
+	vector[name] = false
+
+	a := g.add(vector)
+	b := g.add(flip(vector, name))
+
+	return pair(a, b)
+}
@@ -0,0 +1,44 @@
+package bdd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_MCDC(t *testing.T) {
+	testCases := []struct {
+		expression  string
+		vectors     int
+		uncoverable []string
+	}{
+		{expression: "x", vectors: 2, uncoverable: []string{}},
+		{expression: "x AND y", vectors: 3, uncoverable: []string{}},
+		{expression: "x OR y OR z", vectors: 4, uncoverable: []string{}},
+		{expression: "(x OR y) AND z", vectors: 4, uncoverable: []string{}},
+		{expression: "(a AND b) OR (c AND NOT d)", vectors: 5, uncoverable: []string{}},
+		{expression: "x OR x AND y", vectors: 2, uncoverable: []string{"y"}},
+	}
+
+	for _, tc := range testCases {
+		node := mustParse(t, tc.expression)
+		m, f := mustCompile(t, node)
+		vars := node.Variables()
+
+		got := m.MCDC(f, vars)
+
+		assert.Len(t, got.Vectors, tc.vectors, tc.expression)
+		assert.Equal(t, tc.uncoverable, got.Uncoverable, tc.expression)
+		require.Len(t, got.Pairs, len(vars)-len(tc.uncoverable), tc.expression)
+
+		for _, pair := range got.Pairs {
+			a, b := got.Vectors[pair.FalseVector], got.Vectors[pair.TrueVector]
+
+			// The vectors differ in the variable only and flip the outcome.
+			assert.False(t, a[pair.Variable], tc.expression)
+			assert.Equal(t, flip(a, pair.Variable), b, tc.expression)
+			assert.NotEqual(t, node.Eval(a), node.Eval(b), tc.expression)
+		}
+	}
+}
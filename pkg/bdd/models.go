@@ -0,0 +1,69 @@
+package bdd
+
+// Models enumerates the assignments of vars satisfying f, in lexicographic
+// order with false before true, returning at most limit of them. Variables
+// of f outside vars are projected away. When after is given, only the
+// assignments coming strictly after it are returned, so the last assignment
+// of a page resumes the enumeration.
+func (m *Manager) Models(f Ref, vars []string, after []bool, limit int) [][]bool {
+	projected := map[string]struct{}{}
+	for _, name := range vars {
+		projected[name] = struct{}{}
+	}
+
+	hidden := []string{}
+	for _, name := range m.vars {
+		if _, ok := projected[name]; !ok {
+			hidden = append(hidden, name)
+		}
+	}
+	f = m.Exists(f, hidden...)
+
+	e := &enumerator{
+		m:      m,
+		vars:   vars,
+		after:  after,
+		limit:  limit,
+		models: [][]bool{},
+		prefix: make([]bool, 0, len(vars)),
+	}
+	e.enumerate(f, after != nil)
+
+	return e.models
+}
+
+type enumerator struct {
+	m      *Manager
+	vars   []string
+	after  []bool
+	limit  int
+	models [][]bool
+	prefix []bool
+}
+
+// enumerate extends the prefix with every value of the next variable. While
+// tight, the prefix equals the beginning of after and smaller values are
+// skipped.
+func (e *enumerator) enumerate(f Ref, tight bool) {
+	if f == False || len(e.models) >= e.limit {
+		return
+	}
+
+	i := len(e.prefix)
+	if i == len(e.vars) {
+		if !tight {
+			e.models = append(e.models, append([]bool{}, e.prefix...))
+		}
+		return
+	}
+
+	for _, value := range []bool{false, true} {
+		if tight && !value && e.after[i] {
+			continue
+		}
+
+		e.prefix = append(e.prefix, value)
+		e.enumerate(e.m.Restrict(f, e.vars[i], value), tight && value == e.after[i])
+		e.prefix = e.prefix[:i]
+	}
+}
@@ -0,0 +1,57 @@
+package bdd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Models(t *testing.T) {
+	m := New([]string{"x", "y", "z"})
+	f := m.Compile(mustParse(t, "x OR y AND z"))
+
+	t.Run("enumerates every model in order", func(t *testing.T) {
+		got := m.Models(f, []string{"x", "y", "z"}, nil, 10)
+
+		expect := [][]bool{
+			{false, true, true},
+			{true, false, false},
+			{true, false, true},
+			{true, true, false},
+			{true, true, true},
+		}
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("paginates from the last model", func(t *testing.T) {
+		vars := []string{"x", "y", "z"}
+
+		page := m.Models(f, vars, nil, 2)
+		assert.Equal(t, [][]bool{{false, true, true}, {true, false, false}}, page)
+
+		page = m.Models(f, vars, page[len(page)-1], 2)
+		assert.Equal(t, [][]bool{{true, false, true}, {true, true, false}}, page)
+
+		page = m.Models(f, vars, page[len(page)-1], 2)
+		assert.Equal(t, [][]bool{{true, true, true}}, page)
+
+		page = m.Models(f, vars, page[len(page)-1], 2)
+		assert.Empty(t, page)
+	})
+
+	t.Run("projects onto a subset of variables", func(t *testing.T) {
+		got := m.Models(f, []string{"y", "x"}, nil, 10)
+
+		expect := [][]bool{
+			{false, true},
+			{true, false},
+			{true, true},
+		}
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("enumerates nothing when unsatisfiable", func(t *testing.T) {
+		assert.Empty(t, m.Models(False, []string{"x"}, nil, 10))
+		assert.Equal(t, [][]bool{{}}, m.Models(True, []string{}, nil, 10))
+	})
+}
@@ -0,0 +1,77 @@
+package bdd
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+)
+
+// Ordering is a heuristic choosing the variable order of a tree.
+type Ordering func(n *logic.Node) []string
+
+// OrderByAppearance orders variables as they first appear in the tree, which
+// keeps variables of the same sub-expression close to each other.
+func OrderByAppearance(n *logic.Node) []string {
+	return n.Variables()
+}
+
+// OrderByFrequency puts the variables occurring the most at the top, ties
+// being broken by appearance.
+func OrderByFrequency(n *logic.Node) []string {
+	order := n.Variables()
+
+	occurrences := map[string]int{}
+	n.Walk(func(node *logic.Node) {
+		if node.Kind == logic.KindVariable {
+			occurrences[node.Name]++
+		}
+	})
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return occurrences[order[i]] > occurrences[order[j]]
+	})
+
+	return order
+}
+
+// Orderings lists the heuristics tried by Compile.
+var Orderings = []Ordering{OrderByAppearance, OrderByFrequency}
+
+// Compile compiles the tree with every heuristic of Orderings and returns the
+// smallest diagram, in an unbounded Manager. Each heuristic is bounded by the
+// context and maxNodes, as Manager.Bound does, and Compile fails with
+// ErrTooLarge when all of them exceed maxNodes.
+func Compile(ctx context.Context, n *logic.Node, maxNodes int) (*Manager, Ref, error) {
+	var (
+		best     *Manager
+		bestRoot Ref
+		tooLarge error
+	)
+
+	for _, ordering := range Orderings {
+		m := New(ordering(n))
+		m.Bound(ctx, maxNodes)
+
+		root := m.Compile(n)
+		if err := m.Err(); err != nil {
+			if !errors.Is(err, ErrTooLarge) {
+				return nil, False, err
+			}
+			tooLarge = err
+			continue
+		}
+		m.Bound(nil, 0)
+
+		if best == nil || m.Size(root) < best.Size(bestRoot) {
+			best, bestRoot = m, root
+		}
+	}
+
+	if best == nil {
+		return nil, False, tooLarge
+	}
+
+	return best, bestRoot, nil
+}
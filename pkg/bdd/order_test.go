@@ -0,0 +1,30 @@
+package bdd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderByAppearance(t *testing.T) {
+	node := mustParse(t, "a AND b OR c AND b")
+
+	assert.Equal(t, []string{"a", "b", "c"}, OrderByAppearance(node))
+}
+
+func TestOrderByFrequency(t *testing.T) {
+	node := mustParse(t, "a AND b OR c AND b OR c AND d")
+
+	assert.Equal(t, []string{"b", "c", "a", "d"}, OrderByFrequency(node))
+}
+
+func TestCompile_PicksSmallestOrder(t *testing.T) {
+	// Interleaving the pairs is the known good order for this function, and
+	// it's the one the appearance heuristic finds.
+	node := mustParse(t, "(a AND b) OR (c AND d) OR (e AND f)")
+
+	m, f := mustCompile(t, node)
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, m.Variables())
+	assert.Equal(t, 8, m.Size(f))
+}
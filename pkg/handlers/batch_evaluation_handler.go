@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+// contentTypeNDJSON is the content type of newline-delimited JSON, one
+// parameter set or result per line.
+const contentTypeNDJSON = "application/x-ndjson"
+
+// EvaluateBatch evaluates an expression against the parameter sets of the
+// body, either a JSON array or a NDJSON stream. The results are written in
+// the same order and format, with an error for the sets that couldn't be
+// evaluated.
+func (eh *ExpressionHandler) EvaluateBatch(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	opts, ok := evaluationOptionsFromHeaders(c)
+	if !ok {
+		return
+	}
+
+	ndjson := c.ContentType() == contentTypeNDJSON
+
+	parameters, err := decodeParameterSets(c.Request.Body, ndjson, eh.expressionService.Limits().MaxBatchSize)
+	if err != nil {
+		if errors.Is(err, services.ErrBatchTooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Batch too large",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := eh.expressionService.EvaluateBatch(ctx, int64(expID), parameters, services.BatchEvaluationOptions{
+		EvaluationOptions: opts,
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrBatchTooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Batch too large",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrUnknownMissingParameterPolicy) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported %s header %q", headerMissingParameters, opts.MissingParameters),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Evaluation interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	results := make([]BatchResultResponse, 0, len(res))
+	for _, r := range res {
+		results = append(results, newBatchResultResponse(r))
+	}
+
+	if !ndjson {
+		c.JSON(http.StatusOK, BatchEvaluationResponse{Results: results})
+		return
+	}
+
+	c.Header("Content-Type", contentTypeNDJSON)
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	for _, result := range results {
+		if err := enc.Encode(result); err != nil {
+			return
+		}
+	}
+}
+
+// decodeParameterSets reads the parameter sets of a JSON array or of a
+// NDJSON stream one at a time, failing with services.ErrBatchTooLarge as
+// soon as there are more than maxSets of them so oversized bodies are never
+// read whole. Zero doesn't bound the sets.
+func decodeParameterSets(body io.Reader, ndjson bool, maxSets int) ([]map[string]int, error) {
+	dec := json.NewDecoder(body)
+
+	if !ndjson {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter sets: %w", err)
+		}
+		if tok != json.Delim('[') {
+			return nil, fmt.Errorf("invalid parameter sets: expected a JSON array")
+		}
+	}
+
+	parameters := []map[string]int{}
+	for {
+		if !ndjson && !dec.More() {
+			break
+		}
+
+		var set map[string]int
+		err := dec.Decode(&set)
+		if ndjson && err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter set %d: %w", len(parameters)+1, err)
+		}
+
+		if maxSets > 0 && len(parameters) == maxSets {
+			return nil, fmt.Errorf("%w: more than %d parameter sets", services.ErrBatchTooLarge, maxSets)
+		}
+		parameters = append(parameters, set)
+	}
+
+	if !ndjson {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("invalid parameter sets: %w", err)
+		}
+	}
+
+	return parameters, nil
+}
+
+func newBatchResultResponse(r services.BatchResult) BatchResultResponse {
+	if r.Err != nil {
+		return BatchResultResponse{Error: r.Err.Error()}
+	}
+
+	// Unknown results are null.
+	var resp BatchResultResponse
+	if r.Result != logic.Unknown {
+		result := r.Result == logic.True
+		resp.Result = &result
+	}
+
+	return resp
+}
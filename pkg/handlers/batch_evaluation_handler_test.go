@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionHandler_EvaluateBatch(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(
+		services.WithExpressionRepositoryOption(er),
+		services.WithLimitsOption(services.Limits{MaxBatchSize: 3}),
+	)
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	exp := &repositories.Expression{ID: 1, Value: "x AND y"}
+
+	testCases := []struct {
+		name        string
+		contentType string
+		headers     map[string]string
+		body        string
+		setup       func()
+		status      int
+		expect      string
+	}{
+		{
+			name:   "returns BadRequest when body is invalid",
+			body:   `{"x": 1}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "invalid parameter sets: expected a JSON array"}`,
+		},
+		{
+			name:   "returns BadRequest when a parameter set is invalid",
+			body:   `[{"x": 1}, [1]]`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "invalid parameter set 2: json: cannot unmarshal array into Go value of type map[string]int"}`,
+		},
+		{
+			name:   "returns BadRequest when the array isn't closed",
+			body:   `[{"x": 1}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "invalid parameter set 2: unexpected end of JSON input"}`,
+		},
+		{
+			name:        "returns BadRequest when a NDJSON line is invalid",
+			contentType: contentTypeNDJSON,
+			body:        "{\"x\": 1}\n{x}\n",
+			status:      http.StatusBadRequest,
+			expect:      `{"error": "request invalid", "details": "invalid parameter set 2: invalid character 'x' looking for beginning of object key string"}`,
+		},
+		{
+			name:   "returns RequestEntityTooLarge when the batch is too large",
+			body:   `[{"x": 1}, {"x": 1}, {"x": 1}, {"x": 1}]`,
+			status: http.StatusRequestEntityTooLarge,
+			expect: `{"error": "Batch too large", "details": "batch too large: more than 3 parameter sets"}`,
+		},
+		{
+			name: "stops reading the body after the limit",
+			// The sets past the limit are never decoded.
+			body:   `[{"x": 1}, {"x": 1}, {"x": 1}, {"x": 1}, {x}`,
+			status: http.StatusRequestEntityTooLarge,
+			expect: `{"error": "Batch too large", "details": "batch too large: more than 3 parameter sets"}`,
+		},
+		{
+			name:        "returns RequestEntityTooLarge when the NDJSON stream is too large",
+			contentType: contentTypeNDJSON,
+			body:        "{\"x\": 1}\n{\"x\": 1}\n{\"x\": 1}\n{\"x\": 1}\n{x}\n",
+			status:      http.StatusRequestEntityTooLarge,
+			expect:      `{"error": "Batch too large", "details": "batch too large: more than 3 parameter sets"}`,
+		},
+		{
+			name: "returns NotFound when expression doesn't exist",
+			body: `[{"x": 1}]`,
+			setup: func() {
+				er.On("GetExpressionByID", ctx, int64(1)).Return(nil, repositories.ErrExpressionNotFound).Once()
+			},
+			status: http.StatusNotFound,
+			expect: `{"error": "Expression not found"}`,
+		},
+		{
+			name: "evaluates a JSON array",
+			body: `[{"x": 1, "y": 1}, {"x": 1}, {"x": 0, "y": 1}]`,
+			setup: func() {
+				er.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"results": [
+				{"result": true},
+				{"result": null, "error": "missing parameter \"y\" for the logical expression \"x AND y\""},
+				{"result": false}
+			]}`,
+		},
+		{
+			name:    "applies the evaluation headers",
+			body:    `[{"x": 1}, {"x": 0}]`,
+			headers: map[string]string{headerMissingParameters: "unknown"},
+			setup: func() {
+				er.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"results": [{"result": null}, {"result": false}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			r := gin.Default()
+			r.POST("/evaluate/:id/batch", eh.EvaluateBatch)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/evaluate/1/batch", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.JSONEq(t, tc.expect, string(respBody))
+		})
+	}
+
+	t.Run("evaluates a NDJSON stream", func(t *testing.T) {
+		er.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+
+		r := gin.Default()
+		r.POST("/evaluate/:id/batch", eh.EvaluateBatch)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/evaluate/1/batch", strings.NewReader("{\"x\": 1, \"y\": 1}\n{\"x\": 1, \"y\": 0}\n"))
+		req.Header.Set("Content-Type", contentTypeNDJSON)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, contentTypeNDJSON, resp.Header.Get("Content-Type"))
+		assert.Equal(t, "{\"result\":true}\n{\"result\":false}\n", string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
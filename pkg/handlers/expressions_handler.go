@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/CaioTeixeira95/logic-exp/pkg/bdd"
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
 	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
 	"github.com/CaioTeixeira95/logic-exp/pkg/services"
 	"github.com/gin-gonic/gin"
@@ -14,11 +19,36 @@ import (
 
 type ExpressionHandler struct {
 	expressionService services.ExpressionService
+
+	// adminToken authorizes the admin operations, which are disabled when
+	// it's empty.
+	adminToken string
 }
 
 type ExpressionHandlerOption func(eh *ExpressionHandler)
 
+// The headers setting how EvaluateExpression handles absent and unknown
+// parameters.
+const (
+	headerMissingParameters        = "X-Missing-Parameters"
+	headerMissingParametersDefault = "X-Missing-Parameters-Default"
+	headerStrictParameters         = "X-Strict-Parameters"
+)
+
+// headerAdminToken is the header carrying the token of the admin
+// operations.
+const headerAdminToken = "X-Admin-Token"
+
+// formatJSONLogic is the format query parameter value that makes the
+// expressions endpoints read and write JSONLogic documents.
+const formatJSONLogic = "jsonlogic"
+
 func (eh *ExpressionHandler) CreateExpression(c *gin.Context) {
+	if c.Query("format") == formatJSONLogic {
+		eh.createExpressionFromJSONLogic(c)
+		return
+	}
+
 	var reqBody CreateExpressionRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
 		reqErrs := ParseRequestError(err)
@@ -38,8 +68,93 @@ func (eh *ExpressionHandler) CreateExpression(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	var options []services.CreateExpressionOption
+	if reqBody.Format {
+		options = append(options, services.WithFormatOption(logic.FormatOptions{}))
+	}
+	if reqBody.RegisteredVariables {
+		options = append(options, services.WithRegisteredVariablesOption())
+	}
+
+	exp, err := eh.expressionService.CreateExpression(ctx, &repositories.Expression{
+		Value:     reqBody.Expression,
+		Dialect:   reqBody.Dialect,
+		Namespace: reqBody.Namespace,
+	}, options...)
+	if err != nil {
+		if errors.Is(err, logic.ErrConflictingOperators) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid expression provided",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidExpression) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid expression provided",
+			})
+			return
+		}
+
+		if isLimitError(err) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Expression exceeds the limits",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var unregisteredErr *services.UnregisteredVariableError
+		if errors.As(err, &unregisteredErr) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Unregistered variables",
+				"details": unregisteredErr.Error(),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateExpressionResponse{
+		ExpressionResponse{
+			ID:         exp.ID,
+			Expression: exp.Value,
+			Original:   exp.Original,
+			Dialect:    exp.Dialect,
+			Namespace:  exp.Namespace,
+		},
+	})
+}
+
+func (eh *ExpressionHandler) createExpressionFromJSONLogic(c *gin.Context) {
+	document, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Request invalid in some way",
+		})
+		return
+	}
+
+	node, err := logic.FromJSONLogic(document)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSONLogic provided",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// The body being the document, the namespace is a query parameter.
 	exp, err := eh.expressionService.CreateExpression(ctx, &repositories.Expression{
-		Value: reqBody.Expression,
+		Value:     node.String(),
+		Namespace: c.Query("namespace"),
 	})
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidExpression) {
@@ -49,6 +164,14 @@ func (eh *ExpressionHandler) CreateExpression(c *gin.Context) {
 			return
 		}
 
+		if isLimitError(err) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Expression exceeds the limits",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"error": "An Internal Server error occurred",
 		})
@@ -59,10 +182,115 @@ func (eh *ExpressionHandler) CreateExpression(c *gin.Context) {
 		ExpressionResponse{
 			ID:         exp.ID,
 			Expression: exp.Value,
+			Namespace:  exp.Namespace,
+		},
+	})
+}
+
+func (eh *ExpressionHandler) GetExpression(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	format := c.Query("format")
+	if format != "" && format != formatJSONLogic {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": fmt.Sprintf("unsupported format %q", format),
+		})
+		return
+	}
+
+	if format == formatJSONLogic {
+		eh.getExpressionAsJSONLogic(c, int64(expID))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	details, err := eh.expressionService.DescribeExpression(ctx, int64(expID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	node, err := logic.Parse(details.Value)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, GetExpressionResponse{
+		ExpressionResponse: ExpressionResponse{
+			ID:         details.ID,
+			Expression: details.Value,
+			Original:   details.Original,
+			Dialect:    details.Dialect,
+			Namespace:  details.Namespace,
 		},
+		Description: logic.English(node, logic.EnglishOptions{Labels: c.QueryMap("labels")}),
+		Variables:   details.Variables,
+		Canonical:   details.Canonical,
+		Satisfiable: details.Satisfiable,
+		Tautology:   details.Tautology,
+		CreatedAt:   details.CreatedAt,
+		UpdatedAt:   details.UpdatedAt,
+		Version:     details.Version,
 	})
 }
 
+// getExpressionAsJSONLogic returns the expression as a JSONLogic document,
+// without the metadata derived from it.
+func (eh *ExpressionHandler) getExpressionAsJSONLogic(c *gin.Context, ID int64) {
+	exp, err := eh.expressionService.GetExpression(c.Request.Context(), ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	node, err := logic.Parse(exp.Value)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, logic.ToJSONLogic(node))
+}
+
 func (eh *ExpressionHandler) ListExpressions(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -74,17 +302,24 @@ func (eh *ExpressionHandler) ListExpressions(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, newListExpressionsResponse(exps))
+}
+
+func newListExpressionsResponse(exps []repositories.Expression) []ListExpressionsResponse {
 	respBody := make([]ListExpressionsResponse, 0, len(exps))
 	for _, exp := range exps {
 		respBody = append(respBody, ListExpressionsResponse{
 			ExpressionResponse{
 				ID:         exp.ID,
 				Expression: exp.Value,
+				Original:   exp.Original,
+				Dialect:    exp.Dialect,
+				Namespace:  exp.Namespace,
 			},
 		})
 	}
 
-	c.JSON(http.StatusOK, respBody)
+	return respBody
 }
 
 func (eh *ExpressionHandler) UpdateExpression(c *gin.Context) {
@@ -114,11 +349,21 @@ func (eh *ExpressionHandler) UpdateExpression(c *gin.Context) {
 		return
 	}
 
+	exp := &repositories.Expression{
+		ID:      int64(expID),
+		Value:   reqBody.Expression,
+		Dialect: reqBody.Dialect,
+	}
+
+	var options []services.UpdateExpressionOption
+	if reqBody.Namespace != nil {
+		exp.Namespace = *reqBody.Namespace
+	} else {
+		options = append(options, services.WithKeptNamespaceOption())
+	}
+
 	ctx := c.Request.Context()
-	exp, err := eh.expressionService.UpdateExpression(ctx, &repositories.Expression{
-		ID:    int64(expID),
-		Value: reqBody.Expression,
-	})
+	exp, err = eh.expressionService.UpdateExpression(ctx, exp, options...)
 	if err != nil {
 		if errors.Is(err, repositories.ErrNoRowsAffected) {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
@@ -127,6 +372,14 @@ func (eh *ExpressionHandler) UpdateExpression(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, logic.ErrConflictingOperators) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid expression provided",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		if errors.Is(err, services.ErrInvalidExpression) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
 				"error": "Invalid expression provided",
@@ -134,6 +387,14 @@ func (eh *ExpressionHandler) UpdateExpression(c *gin.Context) {
 			return
 		}
 
+		if isLimitError(err) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Expression exceeds the limits",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"error": "An Internal Server error occurred",
 		})
@@ -144,10 +405,114 @@ func (eh *ExpressionHandler) UpdateExpression(c *gin.Context) {
 		ExpressionResponse: ExpressionResponse{
 			ID:         exp.ID,
 			Expression: exp.Value,
+			Original:   exp.Original,
+			Dialect:    exp.Dialect,
+			Namespace:  exp.Namespace,
 		},
 	})
 }
 
+// DeleteExpression soft deletes the expression, or hard deletes it with
+// the hard query parameter, which requires the admin token.
+func (eh *ExpressionHandler) DeleteExpression(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	var options []services.DeleteExpressionOption
+	if value := c.Query("hard"); value != "" {
+		hard, err := strconv.ParseBool(value)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("error converting to boolean the hard query parameter %q", value),
+			})
+			return
+		}
+
+		if hard {
+			if !eh.isAdmin(c) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error":   "Forbidden",
+					"details": "hard deletes require the admin token",
+				})
+				return
+			}
+
+			options = append(options, services.WithHardDeleteOption())
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	if err := eh.expressionService.DeleteExpression(ctx, int64(expID), options...); err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreExpression restores a soft deleted expression.
+func (eh *ExpressionHandler) RestoreExpression(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	exp, err := eh.expressionService.RestoreExpression(ctx, int64(expID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Deleted expression not found",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExpressionResponse{
+		ID:         exp.ID,
+		Expression: exp.Value,
+		Original:   exp.Original,
+		Dialect:    exp.Dialect,
+		Namespace:  exp.Namespace,
+	})
+}
+
+// isAdmin reports whether the request carries the admin token.
+func (eh *ExpressionHandler) isAdmin(c *gin.Context) bool {
+	if eh.adminToken == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(c.GetHeader(headerAdminToken)), []byte(eh.adminToken)) == 1
+}
+
 func (eh *ExpressionHandler) EvaluateExpression(c *gin.Context) {
 	expID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -180,9 +545,14 @@ func (eh *ExpressionHandler) EvaluateExpression(c *gin.Context) {
 		paramsToEvaluate[key] = val
 	}
 
+	opts, ok := evaluationOptionsFromHeaders(c)
+	if !ok {
+		return
+	}
+
 	ctx := c.Request.Context()
 
-	res, err := eh.expressionService.EvaluateExpression(ctx, int64(expID), paramsToEvaluate)
+	res, err := eh.expressionService.EvaluateExpressionWithOptions(ctx, int64(expID), paramsToEvaluate, opts)
 	if err != nil {
 		if errors.Is(err, repositories.ErrExpressionNotFound) {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
@@ -191,9 +561,48 @@ func (eh *ExpressionHandler) EvaluateExpression(c *gin.Context) {
 			return
 		}
 
-		if strings.Contains(err.Error(), "missing parameter") {
+		var missingErr *services.MissingParameterError
+		if errors.As(err, &missingErr) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
+				"error": missingErr.Error(),
+			})
+			return
+		}
+
+		var unknownErr *services.UnknownParameterError
+		if errors.As(err, &unknownErr) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": unknownErr.Error(),
+			})
+			return
+		}
+
+		var invalidErr *services.InvalidParameterError
+		if errors.As(err, &invalidErr) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": invalidErr.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrUnknownMissingParameterPolicy) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported %s header %q", headerMissingParameters, opts.MissingParameters),
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrBudgetExceeded) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "Evaluation budget exceeded",
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Evaluation interrupted",
 			})
 			return
 		}
@@ -204,11 +613,737 @@ func (eh *ExpressionHandler) EvaluateExpression(c *gin.Context) {
 		return
 	}
 
+	// Unknown results are null.
+	var result interface{}
+	if res != logic.Unknown {
+		result = res == logic.True
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"result": res,
+		"result": result,
+	})
+}
+
+func (eh *ExpressionHandler) FormatExpression(c *gin.Context) {
+	var reqBody FormatExpressionRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		reqErrs := ParseRequestError(err)
+		if len(reqErrs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": reqErrs["details"],
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Request invalid in some way",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	formatted, err := eh.expressionService.FormatExpression(ctx, &repositories.Expression{
+		Value:   reqBody.Expression,
+		Dialect: reqBody.Dialect,
+	}, logic.FormatOptions{Width: reqBody.Width})
+	if err != nil {
+		if errors.Is(err, logic.ErrConflictingOperators) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid expression provided",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidExpression) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid expression provided",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, FormatExpressionResponse{
+		Expression: formatted,
+	})
+}
+
+func (eh *ExpressionHandler) GetExpressionSQL(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	// Every query parameter maps a variable to the column it reads from.
+	columns := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if len(values) != 1 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("expect exact one value for the key %q but %d were provided", key, len(values)),
+			})
+			return
+		}
+
+		columns[key] = values[0]
+	}
+
+	ctx := c.Request.Context()
+
+	fragment, err := eh.expressionService.CompileExpressionToSQL(ctx, int64(expID), logic.SQLOptions{Columns: columns})
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrUnmappedVariable) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExpressionSQLResponse{
+		Where: fragment.Where,
+		Args:  fragment.Args,
+	})
+}
+
+func (eh *ExpressionHandler) GetExpressionGraph(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	format := logic.GraphFormat(c.DefaultQuery("format", string(logic.GraphFormatDOT)))
+	source := services.GraphSource(c.DefaultQuery("source", string(services.GraphSourceAST)))
+
+	// The evaluation trace is overlaid when values are provided as
+	// values[x]=1&values[y]=0.
+	var paramsToEvaluate map[string]int
+	if values, ok := c.GetQueryMap("values"); ok {
+		paramsToEvaluate = make(map[string]int, len(values))
+		for key, value := range values {
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("error converting to integer value %q of the key %q", value, key),
+				})
+				return
+			}
+
+			paramsToEvaluate[key] = val
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	graph, err := eh.expressionService.RenderExpressionGraph(ctx, int64(expID), format, source, paramsToEvaluate)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrUnknownGraphFormat) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported format %q", format),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrUnknownGraphSource) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported source %q", source),
+			})
+			return
+		}
+
+		var missingErr *services.MissingParameterError
+		if errors.As(err, &missingErr) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": missingErr.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrTooLarge) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Expression too complex to analyze",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.String(http.StatusOK, graph)
+}
+
+func (eh *ExpressionHandler) GetExpressionNetlist(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	format := logic.NetlistFormat(c.DefaultQuery("format", string(logic.NetlistFormatVerilog)))
+
+	ctx := c.Request.Context()
+
+	netlist, err := eh.expressionService.ExportNetlist(ctx, int64(expID), format)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrUnknownNetlistFormat) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported format %q", format),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.String(http.StatusOK, netlist)
+}
+
+func (eh *ExpressionHandler) GetExpressionGo(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	code, err := eh.expressionService.GenerateGo(ctx, int64(expID), logic.GoOptions{
+		Package:  c.Query("package"),
+		Function: c.Query("function"),
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrInvalidGoIdentifier) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": "package and function must be valid Go identifiers",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.String(http.StatusOK, code)
+}
+
+func (eh *ExpressionHandler) CheckEquivalence(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	otherID, err := strconv.Atoi(c.Param("other_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := eh.expressionService.CheckEquivalence(ctx, int64(expID), int64(otherID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrTooLarge) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Expression too complex to analyze",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, EquivalenceResponse{
+		Equivalent:     res.Equivalent,
+		Counterexample: res.Counterexample,
+	})
+}
+
+func (eh *ExpressionHandler) AnalyzeExpression(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	// The probability of each variable being true is given as
+	// probabilities[x]=0.3&probabilities[y]=0.5.
+	var probabilities map[string]float64
+	if values, ok := c.GetQueryMap("probabilities"); ok {
+		probabilities = make(map[string]float64, len(values))
+		for key, value := range values {
+			p, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("error converting to float value %q of the key %q", value, key),
+				})
+				return
+			}
+
+			probabilities[key] = p
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := eh.expressionService.AnalyzeExpression(ctx, int64(expID), probabilities)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrInvalidProbability) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrTooLarge) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Expression too complex to analyze",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, AnalysisResponse{
+		Variables:   res.Variables,
+		ModelCount:  res.ModelCount,
+		Fraction:    res.Fraction,
+		Satisfiable: res.Satisfiable,
+		Tautology:   res.Tautology,
+		Probability: res.Probability,
+	})
+}
+
+func (eh *ExpressionHandler) ListModels(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	opts := services.ModelsOptions{
+		Cursor: c.Query("cursor"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		opts.Limit, err = strconv.Atoi(limit)
+		if err != nil || opts.Limit <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": "invalid limit provided",
+			})
+			return
+		}
+	}
+
+	if vars := c.Query("vars"); vars != "" {
+		opts.Variables = strings.Split(vars, ",")
+	}
+
+	ctx := c.Request.Context()
+
+	page, err := eh.expressionService.ListModels(ctx, int64(expID), opts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrUnknownVariable) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrTooLarge) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Expression too complex to analyze",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsResponse{
+		Models:     page.Models,
+		NextCursor: page.NextCursor,
 	})
 }
 
+func (eh *ExpressionHandler) GenerateMCDC(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := eh.expressionService.GenerateMCDC(ctx, int64(expID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, bdd.ErrTooLarge) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Expression too complex to analyze",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Analysis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	respBody := MCDCResponse{
+		TestCases:   make([]MCDCTestCaseResponse, 0, len(res.TestCases)),
+		Coverage:    make([]MCDCCoverageResponse, 0, len(res.Coverage)),
+		Uncoverable: res.Uncoverable,
+	}
+	for _, testCase := range res.TestCases {
+		respBody.TestCases = append(respBody.TestCases, MCDCTestCaseResponse{
+			Parameters: testCase.Parameters,
+			Expected:   testCase.Expected,
+			Request:    testCase.Request,
+		})
+	}
+	for _, coverage := range res.Coverage {
+		respBody.Coverage = append(respBody.Coverage, MCDCCoverageResponse{
+			Variable:  coverage.Variable,
+			FalseCase: coverage.FalseCase,
+			TrueCase:  coverage.TrueCase,
+		})
+	}
+
+	c.JSON(http.StatusOK, respBody)
+}
+
+func (eh *ExpressionHandler) GetKarnaughMap(c *gin.Context) {
+	expID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": "invalid expression ID provided",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	k, err := eh.expressionService.KarnaughMap(ctx, int64(expID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpressionNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Expression not found",
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrTooManyVariables) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("Karnaugh maps support up to %d variables", logic.MaxKarnaughVariables),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	respBody := KarnaughMapResponse{
+		RowVariables:    k.RowVariables,
+		ColumnVariables: k.ColumnVariables,
+		RowLabels:       k.RowLabels,
+		ColumnLabels:    k.ColumnLabels,
+		Cells:           make([][]int, 0, len(k.Cells)),
+		Groups:          make([]KarnaughGroupResponse, 0, len(k.Groups)),
+		Text:            k.String(),
+	}
+	for _, row := range k.Cells {
+		cells := make([]int, 0, len(row))
+		for _, cell := range row {
+			value := 0
+			if cell {
+				value = 1
+			}
+			cells = append(cells, value)
+		}
+		respBody.Cells = append(respBody.Cells, cells)
+	}
+	for _, group := range k.Groups {
+		respBody.Groups = append(respBody.Groups, KarnaughGroupResponse{
+			Term:     group.Term,
+			Selected: group.Selected,
+			Cells:    group.Cells,
+		})
+	}
+
+	c.JSON(http.StatusOK, respBody)
+}
+
+func (eh *ExpressionHandler) SynthesizeExpression(c *gin.Context) {
+	var reqBody SynthesizeExpressionRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		reqErrs := ParseRequestError(err)
+		if len(reqErrs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": reqErrs["details"],
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Request invalid in some way",
+		})
+		return
+	}
+
+	opts := services.SynthesisOptions{
+		Variables:  reqBody.Variables,
+		TruthTable: reqBody.TruthTable,
+		Save:       reqBody.Save,
+	}
+	for _, example := range reqBody.Examples {
+		opts.Examples = append(opts.Examples, services.SynthesisExample{
+			Parameters: example.Parameters,
+			Result:     *example.Result,
+		})
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := eh.expressionService.SynthesizeExpression(ctx, opts)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSynthesis) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Synthesis interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	respBody := SynthesizeExpressionResponse{Expression: res.Expression}
+	if res.Saved != nil {
+		respBody.ID = res.Saved.ID
+
+		c.JSON(http.StatusCreated, respBody)
+		return
+	}
+
+	c.JSON(http.StatusOK, respBody)
+}
+
+// evaluationOptionsFromHeaders reads how absent and unknown parameters are
+// handled from the headers, since the query parameters are all variables.
+// It aborts the request when a header is invalid.
+func evaluationOptionsFromHeaders(c *gin.Context) (services.EvaluationOptions, bool) {
+	var err error
+
+	opts := services.EvaluationOptions{
+		MissingParameters: services.MissingParameterPolicy(c.GetHeader(headerMissingParameters)),
+	}
+	if value := c.GetHeader(headerStrictParameters); value != "" {
+		opts.Strict, err = strconv.ParseBool(value)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("error converting to boolean the %s header %q", headerStrictParameters, value),
+			})
+			return opts, false
+		}
+	}
+	if value := c.GetHeader(headerMissingParametersDefault); value != "" {
+		opts.Default, err = strconv.Atoi(value)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("error converting to integer the %s header %q", headerMissingParametersDefault, value),
+			})
+			return opts, false
+		}
+	}
+
+	return opts, true
+}
+
+// isLimitError reports whether the expression was rejected for exceeding
+// the complexity limits of the service.
+func isLimitError(err error) bool {
+	return errors.Is(err, services.ErrExpressionTooLong) ||
+		errors.Is(err, services.ErrExpressionTooDeep) ||
+		errors.Is(err, services.ErrExpressionTooManyNodes) ||
+		errors.Is(err, services.ErrExpressionTooManyVariables)
+}
+
 func NewExpressionHandler(options ...ExpressionHandlerOption) *ExpressionHandler {
 	eh := &ExpressionHandler{}
 
@@ -224,3 +1359,11 @@ func WithExpressionServiceOption(es services.ExpressionService) ExpressionHandle
 		eh.expressionService = es
 	}
 }
+
+// WithAdminTokenOption sets the token authorizing the admin operations,
+// sent in the X-Admin-Token header.
+func WithAdminTokenOption(token string) ExpressionHandlerOption {
+	return func(eh *ExpressionHandler) {
+		eh.adminToken = token
+	}
+}
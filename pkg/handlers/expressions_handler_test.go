@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
 	"github.com/CaioTeixeira95/logic-exp/pkg/services"
@@ -71,6 +72,25 @@ func TestExpressionHandler_CreateExpression(t *testing.T) {
 		assert.JSONEq(t, `{"error": "request invalid", "details": {"expression": "invalid type provided for this field"}}`, string(respBody))
 	})
 
+	t.Run("returns BadRequest when expression exceeds the limits", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		expression := strings.Repeat("x AND ", 2000) + "x"
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "`+expression+`"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Expression exceeds the limits", "details": "expression too long: 12001 bytes, the limit is 10000"}`, string(respBody))
+	})
+
 	t.Run("returns BadRequest when expression is invalid", func(t *testing.T) {
 		r := gin.Default()
 		r.POST(endpoint, eh.CreateExpression)
@@ -157,10 +177,110 @@ func TestExpressionHandler_CreateExpression(t *testing.T) {
 		assert.JSONEq(t, `{"id":1, "expression":"(x AND z)"}`, string(respBody))
 	})
 
+	t.Run("creates a new expression written in another dialect", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		reqBody := `
+			{
+				"expression": "x && !z"
+			}
+		`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		er.
+			On("CreateExpression", req.Context(), &repositories.Expression{
+				Value:    "x AND NOT z",
+				Original: "x && !z",
+				Dialect:  "c",
+			}).
+			Return(&repositories.Expression{
+				ID:       1,
+				Value:    "x AND NOT z",
+				Original: "x && !z",
+				Dialect:  "c",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"x AND NOT z", "original":"x && !z", "dialect":"c"}`, string(respBody))
+
+		req, _ = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "x && z", "dialect": "pascal"}`))
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp = w.Result()
+
+		respBody, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": {"dialect": "invalid value provided for this field"}}`, string(respBody))
+	})
+
+	t.Run("creates a new expression mixing keywords and symbols", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "x AND !z"}`))
+		w := httptest.NewRecorder()
+
+		er.
+			On("CreateExpression", req.Context(), &repositories.Expression{
+				Value:    "x AND NOT z",
+				Original: "x AND !z",
+				Dialect:  "mixed",
+			}).
+			Return(&repositories.Expression{
+				ID:       1,
+				Value:    "x AND NOT z",
+				Original: "x AND !z",
+				Dialect:  "mixed",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"x AND NOT z", "original":"x AND !z", "dialect":"mixed"}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when the operators conflict", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "x && ¬z"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Invalid expression provided", "details": "invalid expression: conflicting operators: \"&&\" and \"¬\""}`, string(respBody))
+	})
+
 	er.AssertExpectations(t)
 }
 
-func TestExpressionHandler_ListExpressions(t *testing.T) {
+func TestExpressionHandler_CreateExpression_JSONLogic(t *testing.T) {
 	er := &repositories.ExpressionRepositoryMock{}
 	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
 	eh := NewExpressionHandler(WithExpressionServiceOption(es))
@@ -170,16 +290,1491 @@ func TestExpressionHandler_ListExpressions(t *testing.T) {
 	ctx := context.Background()
 	endpoint := "/expressions"
 
-	t.Run("returns InternalServer error when an unexpected error occurs", func(t *testing.T) {
+	t.Run("returns BadRequest when the JSONLogic is invalid", func(t *testing.T) {
 		r := gin.Default()
-		r.GET(endpoint, eh.ListExpressions)
+		r.POST(endpoint, eh.CreateExpression)
 
-		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		reqBody := `{"==": [{"var": "x"}, 1]}`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?format=jsonlogic", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Invalid JSONLogic provided", "details": "invalid JSONLogic: unsupported operation \"==\""}`, string(respBody))
+	})
+
+	t.Run("creates a new expression from JSONLogic successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		reqBody := `{"and": [{"var": "x"}, {"or": [{"var": "y"}, {"!": {"var": "z"}}]}]}`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?format=jsonlogic", strings.NewReader(reqBody))
 		w := httptest.NewRecorder()
 
 		er.
-			On("GetAllExpressions", req.Context()).
-			Return(nil, errors.New("unexpected error")).
+			On("CreateExpression", req.Context(), &repositories.Expression{
+				Value: "x AND (y OR NOT z)",
+			}).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND (y OR NOT z)",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"x AND (y OR NOT z)"}`, string(respBody))
+	})
+
+	t.Run("creates a new expression from JSONLogic in a namespace", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.CreateExpression)
+
+		reqBody := `{"or": [{"var": "x"}, {"var": "y"}]}`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?format=jsonlogic&namespace=billing", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		er.
+			On("CreateExpression", req.Context(), &repositories.Expression{
+				Value:     "x OR y",
+				Namespace: "billing",
+			}).
+			Return(&repositories.Expression{
+				ID:        2,
+				Value:     "x OR y",
+				Namespace: "billing",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"id":2, "expression":"x OR y", "namespace":"billing"}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id"
+
+	t.Run("returns BadRequest when the format is unsupported", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1?format=xml", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "unsupported format \"xml\""}`, string(respBody))
+	})
+
+	t.Run("returns NotFound when expression is not found", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Expression not found"}`, string(respBody))
+	})
+
+	t.Run("returns the expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:        1,
+				Value:     "x AND NOT y",
+				CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+				Version:   2,
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{
+			"id": 1,
+			"expression": "x AND NOT y",
+			"description": "x (but not y)",
+			"variables": ["x", "y"],
+			"canonical": "x AND NOT y",
+			"satisfiable": true,
+			"tautology": false,
+			"created_at": "2026-10-01T12:00:00Z",
+			"updated_at": "2026-10-02T12:00:00Z",
+			"version": 2
+		}`, string(respBody))
+	})
+
+	t.Run("describes the expression with the labels provided", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1?labels[x]=premium&labels[y]=trial", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y AND z",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{
+			"id": 1,
+			"expression": "x OR y AND z",
+			"description": "premium, or both trial and z",
+			"variables": ["x", "y", "z"],
+			"canonical": "x OR y AND z",
+			"satisfiable": true,
+			"tautology": false,
+			"created_at": "0001-01-01T00:00:00Z",
+			"updated_at": "0001-01-01T00:00:00Z",
+			"version": 0
+		}`, string(respBody))
+	})
+
+	t.Run("returns null flags when the expression is too complex", func(t *testing.T) {
+		eh := NewExpressionHandler(WithExpressionServiceOption(services.NewExpressionService(
+			services.WithExpressionRepositoryOption(er),
+			services.WithLimitsOption(services.Limits{MaxDiagramNodes: 4}),
+		)))
+
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y OR z AND k",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{
+			"id": 1,
+			"expression": "x AND y OR z AND k",
+			"description": "both x and y, or both z and k",
+			"variables": ["x", "y", "z", "k"],
+			"canonical": "x AND y OR z AND k",
+			"satisfiable": null,
+			"tautology": null,
+			"created_at": "0001-01-01T00:00:00Z",
+			"updated_at": "0001-01-01T00:00:00Z",
+			"version": 0
+		}`, string(respBody))
+	})
+
+	t.Run("returns the expression as JSONLogic successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1?format=jsonlogic", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND NOT y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"and": [{"var": "x"}, {"!": [{"var": "y"}]}]}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_ListExpressions(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions"
+
+	t.Run("returns InternalServer error when an unexpected error occurs", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.ListExpressions)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetAllExpressions", req.Context()).
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "An Internal Server error occurred"}`, string(respBody))
+	})
+
+	t.Run("lists all expressions successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.ListExpressions)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetAllExpressions", req.Context()).
+			Return([]repositories.Expression{}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `[]`, string(respBody))
+
+		req, _ = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		w = httptest.NewRecorder()
+
+		er.
+			On("GetAllExpressions", req.Context()).
+			Return([]repositories.Expression{
+				{
+					ID:    1,
+					Value: "x AND z",
+				},
+				{
+					ID:    2,
+					Value: "(x AND z) OR y",
+				},
+				{
+					ID:    3,
+					Value: "(x OR b OR (a AND z))",
+				},
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp = w.Result()
+
+		respBody, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		wantsBody := `
+			[
+				{
+					"id": 1,
+					"expression": "x AND z"
+				},
+				{
+					"id": 2,
+					"expression": "(x AND z) OR y"
+				},
+				{
+					"id": 3,
+					"expression": "(x OR b OR (a AND z))"
+				}
+			]
+		`
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, wantsBody, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_UpdateExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id"
+
+	t.Run("returns BadRequest when body is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader("invalid"))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"message":"invalid request"}`, string(respBody))
+
+		req, _ = http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader("{}"))
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp = w.Result()
+
+		respBody, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": {"expression": "this field is required"}}`, string(respBody))
+
+		req, _ = http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(`{"expression": 1}`))
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp = w.Result()
+
+		respBody, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": {"expression": "invalid type provided for this field"}}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when expression is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		reqBody := `
+			{
+				"expression": "(x AND z"
+			}
+		`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Invalid expression provided"}`, string(respBody))
+	})
+
+	t.Run("returns NotFound when expression is not found", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		reqBody := `
+			{
+				"expression": "(x AND z)"
+			}
+		`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		er.
+			On("UpdateExpression", req.Context(), &repositories.Expression{
+				ID:    1,
+				Value: "(x AND z)",
+			}, true).
+			Return(nil, repositories.ErrNoRowsAffected).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Expression not found"}`, string(respBody))
+	})
+
+	t.Run("returns InternalServerError when an unexpected error occurs", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		reqBody := `
+			{
+				"expression": "(x AND z)"
+			}
+		`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		er.
+			On("UpdateExpression", req.Context(), &repositories.Expression{
+				ID:    1,
+				Value: "(x AND z)",
+			}, true).
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.JSONEq(t, `{"error":"An Internal Server error occurred"}`, string(respBody))
+	})
+
+	t.Run("updates an expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		reqBody := `
+			{
+				"expression": "(x AND z)"
+			}
+		`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		er.
+			On("UpdateExpression", req.Context(), &repositories.Expression{
+				ID:    1,
+				Value: "(x AND z)",
+			}, true).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "(x AND z)",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"(x AND z)"}`, string(respBody))
+	})
+
+	t.Run("keeps the namespace when none is given", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(`{"expression": "x"}`))
+		w := httptest.NewRecorder()
+
+		er.
+			On("UpdateExpression", req.Context(), &repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, true).
+			Return(&repositories.Expression{
+				ID:        1,
+				Value:     "x",
+				Namespace: "billing",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"x", "namespace":"billing"}`, string(respBody))
+	})
+
+	t.Run("moves the expression to the namespace given", func(t *testing.T) {
+		r := gin.Default()
+		r.PUT(endpoint, eh.UpdateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(`{"expression": "x", "namespace": ""}`))
+		w := httptest.NewRecorder()
+
+		er.
+			On("UpdateExpression", req.Context(), &repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, false).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1, "expression":"x"}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_DeleteExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es), WithAdminTokenOption("secret"))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		target  string
+		handler *ExpressionHandler
+		headers map[string]string
+		setup   func()
+		status  int
+		expect  string
+	}{
+		{
+			name:   "returns BadRequest when ID is invalid",
+			target: "/expressions/x",
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "invalid expression ID provided"}`,
+		},
+		{
+			name:   "returns BadRequest when hard is invalid",
+			target: "/expressions/1?hard=maybe",
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "error converting to boolean the hard query parameter \"maybe\""}`,
+		},
+		{
+			name:   "returns NotFound when expression doesn't exist",
+			target: "/expressions/1",
+			setup: func() {
+				er.On("DeleteExpression", ctx, int64(1)).Return(repositories.ErrExpressionNotFound).Once()
+			},
+			status: http.StatusNotFound,
+			expect: `{"error": "Expression not found"}`,
+		},
+		{
+			name:   "returns InternalServerError when repository fails",
+			target: "/expressions/1",
+			setup: func() {
+				er.On("DeleteExpression", ctx, int64(1)).Return(errors.New("connection lost")).Once()
+			},
+			status: http.StatusInternalServerError,
+			expect: `{"error": "An Internal Server error occurred"}`,
+		},
+		{
+			name:   "soft deletes the expression",
+			target: "/expressions/1",
+			setup: func() {
+				er.On("DeleteExpression", ctx, int64(1)).Return(nil).Once()
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "soft deletes the expression when hard is false",
+			target: "/expressions/1?hard=false",
+			setup: func() {
+				er.On("DeleteExpression", ctx, int64(1)).Return(nil).Once()
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "returns Forbidden when hard deleting without the admin token",
+			target: "/expressions/1?hard=true",
+			status: http.StatusForbidden,
+			expect: `{"error": "Forbidden", "details": "hard deletes require the admin token"}`,
+		},
+		{
+			name:    "returns Forbidden when hard deleting with a wrong admin token",
+			target:  "/expressions/1?hard=true",
+			headers: map[string]string{headerAdminToken: "guess"},
+			status:  http.StatusForbidden,
+			expect:  `{"error": "Forbidden", "details": "hard deletes require the admin token"}`,
+		},
+		{
+			name:    "returns Forbidden when hard deletes are disabled",
+			target:  "/expressions/1?hard=true",
+			handler: NewExpressionHandler(WithExpressionServiceOption(es)),
+			headers: map[string]string{headerAdminToken: ""},
+			status:  http.StatusForbidden,
+			expect:  `{"error": "Forbidden", "details": "hard deletes require the admin token"}`,
+		},
+		{
+			name:    "hard deletes the expression",
+			target:  "/expressions/1?hard=true",
+			headers: map[string]string{headerAdminToken: "secret"},
+			setup: func() {
+				er.On("HardDeleteExpression", ctx, int64(1)).Return(nil).Once()
+			},
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			handler := eh
+			if tc.handler != nil {
+				handler = tc.handler
+			}
+
+			r := gin.Default()
+			r.DELETE("/expressions/:id", handler.DeleteExpression)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, tc.target, nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			if tc.expect == "" {
+				assert.Empty(t, respBody)
+			} else {
+				assert.JSONEq(t, tc.expect, string(respBody))
+			}
+		})
+	}
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_RestoreExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		target string
+		setup  func()
+		status int
+		expect string
+	}{
+		{
+			name:   "returns BadRequest when ID is invalid",
+			target: "/expressions/x/restore",
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "invalid expression ID provided"}`,
+		},
+		{
+			name:   "returns NotFound when expression isn't deleted",
+			target: "/expressions/1/restore",
+			setup: func() {
+				er.On("RestoreExpression", ctx, int64(1)).Return(nil, repositories.ErrExpressionNotFound).Once()
+			},
+			status: http.StatusNotFound,
+			expect: `{"error": "Deleted expression not found"}`,
+		},
+		{
+			name:   "returns InternalServerError when repository fails",
+			target: "/expressions/1/restore",
+			setup: func() {
+				er.On("RestoreExpression", ctx, int64(1)).Return(nil, errors.New("connection lost")).Once()
+			},
+			status: http.StatusInternalServerError,
+			expect: `{"error": "An Internal Server error occurred"}`,
+		},
+		{
+			name:   "restores the expression",
+			target: "/expressions/1/restore",
+			setup: func() {
+				er.On("RestoreExpression", ctx, int64(1)).
+					Return(&repositories.Expression{ID: 1, Value: "x AND y", Namespace: "billing"}, nil).
+					Once()
+			},
+			status: http.StatusOK,
+			expect: `{"id": 1, "expression": "x AND y", "namespace": "billing"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			r := gin.Default()
+			r.POST("/expressions/:id/restore", eh.RestoreExpression)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, tc.target, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.JSONEq(t, tc.expect, string(respBody))
+		})
+	}
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_FormatExpression(t *testing.T) {
+	es := services.NewExpressionService()
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/format"
+
+	t.Run("returns BadRequest when body is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.FormatExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "x", "width": -1}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": {"width": "value provided for this field is too small"}}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when expression is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.FormatExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "(x AND z"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Invalid expression provided"}`, string(respBody))
+	})
+
+	t.Run("formats an expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.FormatExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"expression": "((x and y)) or (z)"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"expression": "x AND y OR z"}`, string(respBody))
+	})
+}
+
+func TestExpressionHandler_SynthesizeExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/synthesize"
+
+	t.Run("returns BadRequest when body is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.SynthesizeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"examples": [{"parameters": {"x": 1}}]}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": {"result": "this field is required"}}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when the truth table is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.SynthesizeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(`{"variables": ["x"], "truth_table": [true]}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "invalid synthesis request: the truth table of 1 variables must have 2 rows"}`, string(respBody))
+	})
+
+	t.Run("returns ServiceUnavailable when the synthesis is interrupted", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.SynthesizeExpression)
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		rows := make([]string, 1<<10)
+		for row := range rows {
+			rows[row] = fmt.Sprint(row%3 == 0)
+		}
+		body := fmt.Sprintf(`{"variables": ["a", "b", "c", "d", "e", "f", "g", "h", "i", "j"], "truth_table": [%s]}`, strings.Join(rows, ","))
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Synthesis interrupted"}`, string(respBody))
+	})
+
+	t.Run("synthesizes an expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.SynthesizeExpression)
+
+		body := `{"variables": ["x", "y"], "truth_table": [false, true, true, null]}`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"expression": "x OR y"}`, string(respBody))
+	})
+
+	t.Run("saves the synthesized expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.POST(endpoint, eh.SynthesizeExpression)
+
+		body := `{"examples": [{"parameters": {"x": 1, "y": 1}, "result": true}, {"parameters": {"x": 0}, "result": false}], "save": true}`
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		er.
+			On("CreateExpression", req.Context(), &repositories.Expression{Value: "x"}).
+			Return(&repositories.Expression{ID: 1, Value: "x"}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"expression": "x", "id": 1}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetExpressionSQL(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/sql"
+
+	t.Run("returns NotFound when expression is not found", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionSQL)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/sql?x=active", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Expression not found"}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when a variable isn't mapped", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionSQL)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/sql?x=active", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "error compiling expression ID 1 to SQL: variable not mapped to a column: y"}`, string(respBody))
+	})
+
+	t.Run("compiles the expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionSQL)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/sql?x=users.active&y=banned", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "(x OR TRUE) AND NOT y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"where": "(\"users\".\"active\" OR $1) AND NOT \"banned\"", "args": [true]}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetExpressionGraph(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/graph"
+
+	t.Run("returns BadRequest when the format is unsupported", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionGraph)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/graph?format=svg", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "unsupported format \"svg\""}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when a value isn't integer", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionGraph)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/graph?values[x]=abc", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error":"error converting to integer value \"abc\" of the key \"x\""}`, string(respBody))
+	})
+
+	t.Run("renders the expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionGraph)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/graph?values[x]=1", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "NOT x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		expect := `digraph expression {
+  n0 [label="NOT", color=red, style=filled, fillcolor=red];
+  n1 [label="x", color=green, style=filled, fillcolor=green];
+  n0 -> n1 [color=green];
+}
+`
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, expect, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetExpressionNetlist(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/netlist"
+
+	t.Run("returns BadRequest when the format is unsupported", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionNetlist)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/netlist?format=vhdl", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "unsupported format \"vhdl\""}`, string(respBody))
+	})
+
+	t.Run("returns the Verilog module successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionNetlist)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/netlist", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR NOT y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		wantsBody := `module expression_1 (
+  input wire x,
+  input wire y,
+  output wire out
+);
+  assign out = x | ~y;
+endmodule
+`
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, wantsBody, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetExpressionGo(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/go"
+
+	t.Run("returns BadRequest when the package name is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionGo)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/go?package=my-rules", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "package and function must be valid Go identifiers"}`, string(respBody))
+	})
+
+	t.Run("returns the Go code successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetExpressionGo)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/go?package=policy&function=CanCheckout", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR NOT y",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		wantsBody := `// Code generated by logic-exp. DO NOT EDIT.
+
+package policy
+
+// CanCheckout evaluates the logical expression "x OR NOT y".
+func CanCheckout(x, y bool) bool {
+	return x || !y
+}
+`
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, wantsBody, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_CheckEquivalence(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/equivalent/:other_id"
+
+	t.Run("returns NotFound when expression is not found", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.CheckEquivalence)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/equivalent/2", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Expression not found"}`, string(respBody))
+	})
+
+	t.Run("checks equivalence successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.CheckEquivalence)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/equivalent/2", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(2)).
+			Return(&repositories.Expression{
+				ID:    2,
+				Value: "x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"equivalent": false, "counterexample": {"x": 1, "y": 0}}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_AnalyzeExpression(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/analysis"
+
+	t.Run("returns BadRequest when a probability isn't a number", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.AnalyzeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/analysis?probabilities[x]=abc", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "error converting to float value \"abc\" of the key \"x\""}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when a probability is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.AnalyzeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/analysis?probabilities[x]=2", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "invalid probability: \"x\" must be between 0 and 1"}`, string(respBody))
+	})
+
+	t.Run("returns BadRequest when a probability is NaN", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.AnalyzeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/analysis?probabilities[x]=NaN", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
 			Once()
 
 		r.ServeHTTP(w, req)
@@ -189,20 +1784,28 @@ func TestExpressionHandler_ListExpressions(t *testing.T) {
 		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		assert.JSONEq(t, `{"error": "An Internal Server error occurred"}`, string(respBody))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "invalid probability: \"x\" must be between 0 and 1"}`, string(respBody))
 	})
 
-	t.Run("lists all expressions successfully", func(t *testing.T) {
+	t.Run("returns UnprocessableEntity when the expression is too complex", func(t *testing.T) {
+		eh := NewExpressionHandler(WithExpressionServiceOption(services.NewExpressionService(
+			services.WithExpressionRepositoryOption(er),
+			services.WithLimitsOption(services.Limits{MaxDiagramNodes: 4}),
+		)))
+
 		r := gin.Default()
-		r.GET(endpoint, eh.ListExpressions)
+		r.GET(endpoint, eh.AnalyzeExpression)
 
-		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/analysis", nil)
 		w := httptest.NewRecorder()
 
 		er.
-			On("GetAllExpressions", req.Context()).
-			Return([]repositories.Expression{}, nil).
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "(x AND y) OR (z AND k)",
+			}, nil).
 			Once()
 
 		r.ServeHTTP(w, req)
@@ -212,52 +1815,44 @@ func TestExpressionHandler_ListExpressions(t *testing.T) {
 		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		assert.JSONEq(t, `[]`, string(respBody))
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.JSONEq(t, `{
+			"error": "Expression too complex to analyze",
+			"details": "error compiling expression \"(x AND y) OR (z AND k)\": decision diagram too large: more than 4 nodes"
+		}`, string(respBody))
+	})
 
-		req, _ = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-		w = httptest.NewRecorder()
+	t.Run("analyzes the expression successfully", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.AnalyzeExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/analysis?probabilities[x]=0.5&probabilities[y]=0.5", nil)
+		w := httptest.NewRecorder()
 
 		er.
-			On("GetAllExpressions", req.Context()).
-			Return([]repositories.Expression{
-				{
-					ID:    1,
-					Value: "x AND z",
-				},
-				{
-					ID:    2,
-					Value: "(x AND z) OR y",
-				},
-				{
-					ID:    3,
-					Value: "(x OR b OR (a AND z))",
-				},
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
 			}, nil).
 			Once()
 
 		r.ServeHTTP(w, req)
 
-		resp = w.Result()
+		resp := w.Result()
 
-		respBody, err = io.ReadAll(resp.Body)
+		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
 		wantsBody := `
-			[
-				{
-					"id": 1,
-					"expression": "x AND z"
-				},
-				{
-					"id": 2,
-					"expression": "(x AND z) OR y"
-				},
-				{
-					"id": 3,
-					"expression": "(x OR b OR (a AND z))"
-				}
-			]
+			{
+				"variables": ["x", "y"],
+				"model_count": 3,
+				"fraction": 0.75,
+				"satisfiable": true,
+				"tautology": false,
+				"probability": 0.75
+			}
 		`
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -267,7 +1862,7 @@ func TestExpressionHandler_ListExpressions(t *testing.T) {
 	er.AssertExpectations(t)
 }
 
-func TestExpressionHandler_UpdateExpression(t *testing.T) {
+func TestExpressionHandler_ListModels(t *testing.T) {
 	er := &repositories.ExpressionRepositoryMock{}
 	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
 	eh := NewExpressionHandler(WithExpressionServiceOption(es))
@@ -275,13 +1870,13 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 	t.Setenv("GIN_MODE", gin.TestMode)
 
 	ctx := context.Background()
-	endpoint := "/expressions/:id"
+	endpoint := "/expressions/:id/models"
 
-	t.Run("returns BadRequest when body is invalid", func(t *testing.T) {
+	t.Run("returns BadRequest when the limit is invalid", func(t *testing.T) {
 		r := gin.Default()
-		r.PUT(endpoint, eh.UpdateExpression)
+		r.GET(endpoint, eh.ListModels)
 
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader("invalid"))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/models?limit=-1", nil)
 		w := httptest.NewRecorder()
 
 		r.ServeHTTP(w, req)
@@ -292,47 +1887,50 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		assert.JSONEq(t, `{"message":"invalid request"}`, string(respBody))
-
-		req, _ = http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader("{}"))
-		w = httptest.NewRecorder()
-
-		r.ServeHTTP(w, req)
-
-		resp = w.Result()
+		assert.JSONEq(t, `{"error": "request invalid", "details": "invalid limit provided"}`, string(respBody))
+	})
 
-		respBody, err = io.ReadAll(resp.Body)
-		require.NoError(t, err)
+	t.Run("returns BadRequest when the cursor is invalid", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.ListModels)
 
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		assert.JSONEq(t, `{"error": "request invalid", "details": {"expression": "this field is required"}}`, string(respBody))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/models?cursor=abc", nil)
+		w := httptest.NewRecorder()
 
-		req, _ = http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(`{"expression": 1}`))
-		w = httptest.NewRecorder()
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
 
 		r.ServeHTTP(w, req)
 
-		resp = w.Result()
+		resp := w.Result()
 
-		respBody, err = io.ReadAll(resp.Body)
+		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		assert.JSONEq(t, `{"error": "request invalid", "details": {"expression": "invalid type provided for this field"}}`, string(respBody))
+		assert.JSONEq(t, `{"error": "invalid cursor"}`, string(respBody))
 	})
 
-	t.Run("returns BadRequest when expression is invalid", func(t *testing.T) {
+	t.Run("lists the models successfully", func(t *testing.T) {
 		r := gin.Default()
-		r.PUT(endpoint, eh.UpdateExpression)
+		r.GET(endpoint, eh.ListModels)
 
-		reqBody := `
-			{
-				"expression": "(x AND z"
-			}
-		`
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/models?vars=y,x", nil)
 		w := httptest.NewRecorder()
 
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND NOT y",
+			}, nil).
+			Once()
+
 		r.ServeHTTP(w, req)
 
 		resp := w.Result()
@@ -340,28 +1938,33 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		assert.JSONEq(t, `{"error": "Invalid expression provided"}`, string(respBody))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"models": [{"x": 1, "y": 0}]}`, string(respBody))
 	})
 
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GenerateMCDC(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/mcdc"
+
 	t.Run("returns NotFound when expression is not found", func(t *testing.T) {
 		r := gin.Default()
-		r.PUT(endpoint, eh.UpdateExpression)
+		r.GET(endpoint, eh.GenerateMCDC)
 
-		reqBody := `
-			{
-				"expression": "(x AND z)"
-			}
-		`
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/mcdc", nil)
 		w := httptest.NewRecorder()
 
 		er.
-			On("UpdateExpression", req.Context(), &repositories.Expression{
-				ID:    1,
-				Value: "(x AND z)",
-			}).
-			Return(nil, repositories.ErrNoRowsAffected).
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
 			Once()
 
 		r.ServeHTTP(w, req)
@@ -375,24 +1978,71 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 		assert.JSONEq(t, `{"error": "Expression not found"}`, string(respBody))
 	})
 
-	t.Run("returns InternalServerError when an unexpected error occurs", func(t *testing.T) {
+	t.Run("generates the test cases successfully", func(t *testing.T) {
 		r := gin.Default()
-		r.PUT(endpoint, eh.UpdateExpression)
+		r.GET(endpoint, eh.GenerateMCDC)
 
-		reqBody := `
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/mcdc", nil)
+		w := httptest.NewRecorder()
+
+		er.
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "NOT x",
+			}, nil).
+			Once()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		wantsBody := `
 			{
-				"expression": "(x AND z)"
+				"test_cases": [
+					{"parameters": {"x": 0}, "expected": true, "request": "/evaluate/1?x=0"},
+					{"parameters": {"x": 1}, "expected": false, "request": "/evaluate/1?x=1"}
+				],
+				"coverage": [
+					{"variable": "x", "false_case": 0, "true_case": 1}
+				],
+				"uncoverable": []
 			}
 		`
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, wantsBody, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_GetKarnaughMap(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/expressions/:id/kmap"
+
+	t.Run("returns BadRequest when the expression has too many variables", func(t *testing.T) {
+		r := gin.Default()
+		r.GET(endpoint, eh.GetKarnaughMap)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/kmap", nil)
 		w := httptest.NewRecorder()
 
 		er.
-			On("UpdateExpression", req.Context(), &repositories.Expression{
+			On("GetExpressionByID", req.Context(), int64(1)).
+			Return(&repositories.Expression{
 				ID:    1,
-				Value: "(x AND z)",
-			}).
-			Return(nil, errors.New("unexpected error")).
+				Value: "a AND b AND c AND d AND e AND f AND g",
+			}, nil).
 			Once()
 
 		r.ServeHTTP(w, req)
@@ -402,30 +2052,22 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		assert.JSONEq(t, `{"error":"An Internal Server error occurred"}`, string(respBody))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "request invalid", "details": "Karnaugh maps support up to 6 variables"}`, string(respBody))
 	})
 
-	t.Run("updates an expression successfully", func(t *testing.T) {
+	t.Run("returns the map successfully", func(t *testing.T) {
 		r := gin.Default()
-		r.PUT(endpoint, eh.UpdateExpression)
+		r.GET(endpoint, eh.GetKarnaughMap)
 
-		reqBody := `
-			{
-				"expression": "(x AND z)"
-			}
-		`
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/expressions/1", strings.NewReader(reqBody))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/expressions/1/kmap", nil)
 		w := httptest.NewRecorder()
 
 		er.
-			On("UpdateExpression", req.Context(), &repositories.Expression{
-				ID:    1,
-				Value: "(x AND z)",
-			}).
+			On("GetExpressionByID", req.Context(), int64(1)).
 			Return(&repositories.Expression{
 				ID:    1,
-				Value: "(x AND z)",
+				Value: "x AND y",
 			}, nil).
 			Once()
 
@@ -436,8 +2078,22 @@ func TestExpressionHandler_UpdateExpression(t *testing.T) {
 		respBody, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
+		wantsBody := `
+			{
+				"row_variables": ["x"],
+				"column_variables": ["y"],
+				"row_labels": ["0", "1"],
+				"column_labels": ["0", "1"],
+				"cells": [[0, 0], [0, 1]],
+				"groups": [
+					{"term": "x AND y", "selected": true, "cells": [[1, 1]]}
+				],
+				"text": "x\\y | 0 1\n----+----\n  0 | 0 0\n  1 | 0 1\n\nPrime implicants (* selected):\n* x AND y\n"
+			}
+		`
+
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		assert.JSONEq(t, `{"id":1, "expression":"(x AND z)"}`, string(respBody))
+		assert.JSONEq(t, wantsBody, string(respBody))
 	})
 
 	er.AssertExpectations(t)
@@ -621,3 +2277,157 @@ func TestExpressionHandler_EvaluateExpression(t *testing.T) {
 
 	er.AssertExpectations(t)
 }
+
+func TestExpressionHandler_EvaluateExpression_Parameters(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/evaluate/:id"
+
+	testCases := []struct {
+		name       string
+		query      string
+		headers    map[string]string
+		wantsCode  int
+		wantsBody  string
+		repository bool
+	}{
+		{
+			name:       "fails by default",
+			wantsCode:  http.StatusBadRequest,
+			wantsBody:  `{"error": "missing parameter \"y\" for the logical expression \"x AND NOT y\""}`,
+			repository: true,
+		},
+		{
+			name:      "rejects unknown policies",
+			headers:   map[string]string{"X-Missing-Parameters": "ignore"},
+			wantsCode: http.StatusBadRequest,
+			wantsBody: `{"error": "request invalid", "details": "unsupported X-Missing-Parameters header \"ignore\""}`,
+		},
+		{
+			name:      "rejects non-integer defaults",
+			headers:   map[string]string{"X-Missing-Parameters": "default", "X-Missing-Parameters-Default": "yes"},
+			wantsCode: http.StatusBadRequest,
+			wantsBody: `{"error": "error converting to integer the X-Missing-Parameters-Default header \"yes\""}`,
+		},
+		{
+			name:       "rejects unknown parameters when strict",
+			query:      "x=1&y=0&usr=1",
+			headers:    map[string]string{"X-Strict-Parameters": "true"},
+			wantsCode:  http.StatusBadRequest,
+			wantsBody:  `{"error": "unknown parameter \"usr\" for the logical expression \"x AND NOT y\""}`,
+			repository: true,
+		},
+		{
+			name:      "rejects non-boolean strict header",
+			headers:   map[string]string{"X-Strict-Parameters": "maybe"},
+			wantsCode: http.StatusBadRequest,
+			wantsBody: `{"error": "error converting to boolean the X-Strict-Parameters header \"maybe\""}`,
+		},
+		{
+			name:       "evaluates absent variables as false",
+			headers:    map[string]string{"X-Missing-Parameters": "false"},
+			wantsCode:  http.StatusOK,
+			wantsBody:  `{"result": true}`,
+			repository: true,
+		},
+		{
+			name:       "evaluates absent variables as the default",
+			headers:    map[string]string{"X-Missing-Parameters": "default", "X-Missing-Parameters-Default": "1"},
+			wantsCode:  http.StatusOK,
+			wantsBody:  `{"result": false}`,
+			repository: true,
+		},
+		{
+			name:       "evaluates absent variables as unknown",
+			headers:    map[string]string{"X-Missing-Parameters": "unknown"},
+			wantsCode:  http.StatusOK,
+			wantsBody:  `{"result": null}`,
+			repository: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.Default()
+			r.GET(endpoint, eh.EvaluateExpression)
+
+			query := tc.query
+			if query == "" {
+				query = "x=1"
+			}
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/evaluate/1?"+query, nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			if tc.repository {
+				er.
+					On("GetExpressionByID", req.Context(), int64(1)).
+					Return(&repositories.Expression{
+						ID:    1,
+						Value: "x AND NOT y",
+					}, nil).
+					Once()
+			}
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantsCode, resp.StatusCode)
+			assert.JSONEq(t, tc.wantsBody, string(respBody))
+		})
+	}
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_EvaluateExpression_Budget(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(
+		services.WithExpressionRepositoryOption(er),
+		services.WithLimitsOption(services.Limits{MaxEvaluationSteps: 2}),
+	)
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/evaluate/:id"
+
+	r := gin.Default()
+	r.GET(endpoint, eh.EvaluateExpression)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/evaluate/1?x=1&y=1", nil)
+	w := httptest.NewRecorder()
+
+	er.
+		On("GetExpressionByID", req.Context(), int64(1)).
+		Return(&repositories.Expression{
+			ID:    1,
+			Value: "x AND y",
+		}, nil).
+		Once()
+
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.JSONEq(t, `{"error": "Evaluation budget exceeded"}`, string(respBody))
+
+	er.AssertExpectations(t)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MatchExpressions returns the IDs of the expressions matching the
+// parameters of the body, restricted to the namespace query parameter when
+// given.
+func (eh *ExpressionHandler) MatchExpressions(c *gin.Context) {
+	var parameters map[string]int
+	if err := c.ShouldBindJSON(&parameters); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "request invalid",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	opts, ok := evaluationOptionsFromHeaders(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	matches, err := eh.expressionService.MatchExpressions(ctx, parameters, services.MatchOptions{
+		EvaluationOptions: opts,
+		Namespace:         c.Query("namespace"),
+	})
+	if err != nil {
+		var invalidErr *services.InvalidParameterError
+		if errors.As(err, &invalidErr) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": invalidErr.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrUnknownMissingParameterPolicy) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": fmt.Sprintf("unsupported %s header %q", headerMissingParameters, opts.MissingParameters),
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrBudgetExceeded) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "Evaluation budget exceeded",
+			})
+			return
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Evaluation interrupted",
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MatchExpressionsResponse{Matches: matches})
+}
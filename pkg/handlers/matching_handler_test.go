@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionHandler_MatchExpressions(t *testing.T) {
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	exps := []repositories.Expression{
+		{ID: 1, Value: "x AND y", Namespace: "billing"},
+		{ID: 2, Value: "x OR z"},
+		{ID: 3, Value: "NOT x", Namespace: "billing"},
+	}
+
+	testCases := []struct {
+		name    string
+		query   string
+		headers map[string]string
+		body    string
+		setup   func(er *repositories.ExpressionRepositoryMock)
+		status  int
+		expect  string
+	}{
+		{
+			name:   "returns BadRequest when body is invalid",
+			body:   `[{"x": 1}]`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": "json: cannot unmarshal array into Go value of type map[string]int"}`,
+		},
+		{
+			name:    "returns BadRequest when the missing parameter policy is unknown",
+			body:    `{"x": 1}`,
+			headers: map[string]string{headerMissingParameters: "maybe"},
+			status:  http.StatusBadRequest,
+			expect:  `{"error": "request invalid", "details": "unsupported X-Missing-Parameters header \"maybe\""}`,
+		},
+		{
+			name: "returns InternalServerError when the expressions can't be listed",
+			body: `{"x": 1}`,
+			setup: func(er *repositories.ExpressionRepositoryMock) {
+				er.On("GetAllExpressions", ctx).Return(nil, errors.New("connection lost")).Once()
+			},
+			status: http.StatusInternalServerError,
+			expect: `{"error": "An Internal Server error occurred"}`,
+		},
+		{
+			name: "returns the matching expressions",
+			body: `{"x": 1, "y": 1, "z": 0}`,
+			setup: func(er *repositories.ExpressionRepositoryMock) {
+				er.On("GetAllExpressions", ctx).Return(append([]repositories.Expression(nil), exps...), nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"matches": [1, 2]}`,
+		},
+		{
+			name:  "restricts the matching to the namespace",
+			query: "?namespace=billing",
+			body:  `{"x": 0}`,
+			setup: func(er *repositories.ExpressionRepositoryMock) {
+				er.On("GetAllExpressions", ctx).Return(append([]repositories.Expression(nil), exps...), nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"matches": [3]}`,
+		},
+		{
+			name:    "applies the evaluation headers",
+			body:    `{"x": 1}`,
+			headers: map[string]string{headerMissingParameters: "default", headerMissingParametersDefault: "1"},
+			setup: func(er *repositories.ExpressionRepositoryMock) {
+				er.On("GetAllExpressions", ctx).Return(append([]repositories.Expression(nil), exps...), nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"matches": [1, 2]}`,
+		},
+		{
+			name: "returns no match",
+			body: `{"x": 0, "z": 0}`,
+			setup: func(er *repositories.ExpressionRepositoryMock) {
+				er.On("GetAllExpressions", ctx).Return([]repositories.Expression{exps[1]}, nil).Once()
+			},
+			status: http.StatusOK,
+			expect: `{"matches": []}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The expressions are indexed on the first matching, so every
+			// case needs its own service.
+			er := &repositories.ExpressionRepositoryMock{}
+			es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+			eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+			if tc.setup != nil {
+				tc.setup(er)
+			}
+
+			r := gin.Default()
+			r.POST("/evaluate", eh.MatchExpressions)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/evaluate"+tc.query, strings.NewReader(tc.body))
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.JSONEq(t, tc.expect, string(respBody))
+			er.AssertExpectations(t)
+		})
+	}
+}
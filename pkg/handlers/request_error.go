@@ -12,6 +12,10 @@ func msgForTag(tag string) string {
 	switch tag {
 	case "required":
 		return "this field is required"
+	case "oneof":
+		return "invalid value provided for this field"
+	case "min":
+		return "value provided for this field is too small"
 	}
 	return ""
 }
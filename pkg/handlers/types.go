@@ -1,26 +1,63 @@
 package handlers
 
+import (
+	"math/big"
+	"time"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+)
+
 type ExpressionRequest struct {
 	Expression string `json:"expression" binding:"required"`
+	Dialect    string `json:"dialect" binding:"omitempty,oneof=canonical c lowercase unicode"`
 }
 
 type CreateExpressionRequest struct {
 	ExpressionRequest
+	Namespace string `json:"namespace"`
+	Format    bool   `json:"format"`
+	// RegisteredVariables rejects expressions using unregistered variables.
+	RegisteredVariables bool `json:"registered_variables"`
 }
 
 type UpdateExpressionRequest struct {
 	ExpressionRequest
+	// Namespace moves the expression when given, and keeps it in its
+	// namespace otherwise.
+	Namespace *string `json:"namespace"`
+}
+
+type FormatExpressionRequest struct {
+	ExpressionRequest
+	Width int `json:"width" binding:"min=0"`
 }
 
 type ExpressionResponse struct {
 	ID         int64  `json:"id"`
 	Expression string `json:"expression"`
+	Original   string `json:"original,omitempty"`
+	Dialect    string `json:"dialect,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
 }
 
 type CreateExpressionResponse struct {
 	ExpressionResponse
 }
 
+type GetExpressionResponse struct {
+	ExpressionResponse
+	Description string   `json:"description"`
+	Variables   []string `json:"variables"`
+	Canonical   string   `json:"canonical"`
+	// Satisfiable and Tautology are null when the expression is too complex
+	// to decide them.
+	Satisfiable *bool     `json:"satisfiable"`
+	Tautology   *bool     `json:"tautology"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int       `json:"version"`
+}
+
 type ListExpressionsResponse struct {
 	ExpressionResponse
 }
@@ -28,3 +65,143 @@ type ListExpressionsResponse struct {
 type UpdateExpressionResponse struct {
 	ExpressionResponse
 }
+
+type FormatExpressionResponse struct {
+	Expression string `json:"expression"`
+}
+
+type ExpressionSQLResponse struct {
+	Where string        `json:"where"`
+	Args  []interface{} `json:"args"`
+}
+
+type EquivalenceResponse struct {
+	Equivalent     bool           `json:"equivalent"`
+	Counterexample map[string]int `json:"counterexample,omitempty"`
+}
+
+type AnalysisResponse struct {
+	Variables   []string `json:"variables"`
+	ModelCount  *big.Int `json:"model_count"`
+	Fraction    float64  `json:"fraction"`
+	Satisfiable bool     `json:"satisfiable"`
+	Tautology   bool     `json:"tautology"`
+	Probability *float64 `json:"probability,omitempty"`
+}
+
+type ModelsResponse struct {
+	Models     []map[string]int `json:"models"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type MCDCResponse struct {
+	TestCases   []MCDCTestCaseResponse `json:"test_cases"`
+	Coverage    []MCDCCoverageResponse `json:"coverage"`
+	Uncoverable []string               `json:"uncoverable"`
+}
+
+type MCDCTestCaseResponse struct {
+	Parameters map[string]int `json:"parameters"`
+	Expected   bool           `json:"expected"`
+	Request    string         `json:"request"`
+}
+
+type MCDCCoverageResponse struct {
+	Variable  string `json:"variable"`
+	FalseCase int    `json:"false_case"`
+	TrueCase  int    `json:"true_case"`
+}
+
+type SynthesizeExpressionRequest struct {
+	Variables  []string                  `json:"variables"`
+	TruthTable []*bool                   `json:"truth_table"`
+	Examples   []SynthesisExampleRequest `json:"examples" binding:"dive"`
+	Save       bool                      `json:"save"`
+}
+
+type SynthesisExampleRequest struct {
+	Parameters map[string]int `json:"parameters"`
+	Result     *bool          `json:"result" binding:"required"`
+}
+
+type SynthesizeExpressionResponse struct {
+	Expression string `json:"expression"`
+	ID         int64  `json:"id,omitempty"`
+}
+
+type KarnaughMapResponse struct {
+	RowVariables    []string                `json:"row_variables"`
+	ColumnVariables []string                `json:"column_variables"`
+	RowLabels       []string                `json:"row_labels"`
+	ColumnLabels    []string                `json:"column_labels"`
+	Cells           [][]int                 `json:"cells"`
+	Groups          []KarnaughGroupResponse `json:"groups"`
+	Text            string                  `json:"text"`
+}
+
+type KarnaughGroupResponse struct {
+	Term     string   `json:"term"`
+	Selected bool     `json:"selected"`
+	Cells    [][2]int `json:"cells"`
+}
+
+type VariableRequest struct {
+	Type        string `json:"type" binding:"required,oneof=boolean integer enum"`
+	Min         *int   `json:"min"`
+	Max         *int   `json:"max"`
+	Enum        []int  `json:"enum"`
+	Default     *int   `json:"default"`
+	Description string `json:"description"`
+}
+
+func (r VariableRequest) toVariable(name string) *repositories.Variable {
+	return &repositories.Variable{
+		Name:        name,
+		Type:        r.Type,
+		Min:         r.Min,
+		Max:         r.Max,
+		Enum:        r.Enum,
+		Default:     r.Default,
+		Description: r.Description,
+	}
+}
+
+type CreateVariableRequest struct {
+	Name string `json:"name" binding:"required"`
+	VariableRequest
+}
+
+type UpdateVariableRequest struct {
+	VariableRequest
+}
+
+type VariableResponse struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Min         *int   `json:"min,omitempty"`
+	Max         *int   `json:"max,omitempty"`
+	Enum        []int  `json:"enum,omitempty"`
+	Default     *int   `json:"default,omitempty"`
+	Description string `json:"description"`
+}
+
+type RenameVariableRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type BatchEvaluationResponse struct {
+	Results []BatchResultResponse `json:"results"`
+}
+
+// BatchResultResponse holds the result of a parameter set, null when it's
+// unknown, or why it couldn't be evaluated.
+type BatchResultResponse struct {
+	Result *bool  `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MatchExpressionsResponse holds the IDs of the matching expressions,
+// sorted.
+type MatchExpressionsResponse struct {
+	Matches []int64 `json:"matches"`
+}
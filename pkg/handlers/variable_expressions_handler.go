@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListVariableExpressions lists the expressions using a variable.
+func (eh *ExpressionHandler) ListVariableExpressions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	exps, err := eh.expressionService.ListExpressionsByVariable(ctx, c.Param("name"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newListExpressionsResponse(exps))
+}
+
+// RenameVariable renames a variable across every expression, returning the
+// rewritten expressions.
+func (eh *ExpressionHandler) RenameVariable(c *gin.Context) {
+	var reqBody RenameVariableRequest
+	if !bindVariableRequest(c, &reqBody) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	exps, err := eh.expressionService.RenameVariable(ctx, c.Param("name"), reqBody.Name)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidVariable) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid variable provided",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, logic.ErrVariableInUse) || errors.Is(err, repositories.ErrVariableAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error":   "Variable already in use",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newListExpressionsResponse(exps))
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionHandler_ListVariableExpressions(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	er.On("GetExpressionsByVariable", ctx, "x").
+		Return([]repositories.Expression{{ID: 1, Value: "x AND y"}, {ID: 4, Value: "NOT x"}}, nil).
+		Once()
+
+	r := gin.Default()
+	r.GET("/variables/:name/expressions", eh.ListVariableExpressions)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/variables/x/expressions", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `[{"id": 1, "expression": "x AND y"}, {"id": 4, "expression": "NOT x"}]`, string(respBody))
+
+	er.AssertExpectations(t)
+}
+
+func TestExpressionHandler_RenameVariable(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	es := services.NewExpressionService(services.WithExpressionRepositoryOption(er))
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		body   string
+		setup  func()
+		status int
+		expect string
+	}{
+		{
+			name:   "returns BadRequest when the name is missing",
+			body:   `{}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": {"name": "this field is required"}}`,
+		},
+		{
+			name:   "returns BadRequest when the name is invalid",
+			body:   `{"name": "User"}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "Invalid variable provided", "details": "invalid variable: name \"User\" must be made of lowercase letters only"}`,
+		},
+		{
+			name: "returns Conflict when an expression uses the name",
+			body: `{"name": "admin"}`,
+			setup: func() {
+				er.On("RenameVariable", ctx, "usr", "admin").
+					Return([]repositories.Expression{{ID: 1, Value: "usr AND admin"}}, nil).
+					Once()
+			},
+			status: http.StatusConflict,
+			expect: `{"error": "Variable already in use", "details": "error renaming variable \"usr\" to \"admin\": variable already in use: \"admin\""}`,
+		},
+		{
+			name: "renames the variable successfully",
+			body: `{"name": "user"}`,
+			setup: func() {
+				er.On("RenameVariable", ctx, "usr", "user").
+					Return([]repositories.Expression{{ID: 1, Value: "usr AND admin"}}, nil).
+					Once()
+			},
+			status: http.StatusOK,
+			expect: `[{"id": 1, "expression": "user AND admin"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			r := gin.Default()
+			r.POST("/variables/:name/rename", eh.RenameVariable)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/variables/usr/rename", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.JSONEq(t, tc.expect, string(respBody))
+		})
+	}
+
+	er.AssertExpectations(t)
+}
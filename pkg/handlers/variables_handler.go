@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+type VariableHandler struct {
+	variableService services.VariableService
+}
+
+type VariableHandlerOption func(vh *VariableHandler)
+
+func (vh *VariableHandler) CreateVariable(c *gin.Context) {
+	var reqBody CreateVariableRequest
+	if !bindVariableRequest(c, &reqBody) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	v, err := vh.variableService.CreateVariable(ctx, reqBody.toVariable(reqBody.Name))
+	if err != nil {
+		if errors.Is(err, repositories.ErrVariableAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error": "Variable already exists",
+			})
+			return
+		}
+
+		abortWithVariableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newVariableResponse(v))
+}
+
+func (vh *VariableHandler) ListVariables(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	vars, err := vh.variableService.ListVariables(ctx)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "An Internal Server error occurred",
+		})
+		return
+	}
+
+	respBody := make([]VariableResponse, 0, len(vars))
+	for i := range vars {
+		respBody = append(respBody, newVariableResponse(&vars[i]))
+	}
+
+	c.JSON(http.StatusOK, respBody)
+}
+
+func (vh *VariableHandler) GetVariable(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	v, err := vh.variableService.GetVariable(ctx, c.Param("name"))
+	if err != nil {
+		abortWithVariableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVariableResponse(v))
+}
+
+func (vh *VariableHandler) UpdateVariable(c *gin.Context) {
+	var reqBody UpdateVariableRequest
+	if !bindVariableRequest(c, &reqBody) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	v, err := vh.variableService.UpdateVariable(ctx, reqBody.toVariable(c.Param("name")))
+	if err != nil {
+		abortWithVariableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVariableResponse(v))
+}
+
+func (vh *VariableHandler) DeleteVariable(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := vh.variableService.DeleteVariable(ctx, c.Param("name")); err != nil {
+		abortWithVariableError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindVariableRequest binds the request body, aborting the request when
+// it's invalid.
+func bindVariableRequest(c *gin.Context, reqBody interface{}) bool {
+	if err := c.ShouldBindJSON(reqBody); err != nil {
+		reqErrs := ParseRequestError(err)
+		if len(reqErrs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "request invalid",
+				"details": reqErrs["details"],
+			})
+			return false
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Request invalid in some way",
+		})
+		return false
+	}
+
+	return true
+}
+
+func abortWithVariableError(c *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrVariableNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Variable not found",
+		})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidVariable) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid variable provided",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		"error": "An Internal Server error occurred",
+	})
+}
+
+func newVariableResponse(v *repositories.Variable) VariableResponse {
+	return VariableResponse{
+		Name:        v.Name,
+		Type:        v.Type,
+		Min:         v.Min,
+		Max:         v.Max,
+		Enum:        v.Enum,
+		Default:     v.Default,
+		Description: v.Description,
+	}
+}
+
+func NewVariableHandler(options ...VariableHandlerOption) *VariableHandler {
+	vh := &VariableHandler{}
+
+	for _, option := range options {
+		option(vh)
+	}
+
+	return vh
+}
+
+func WithVariableServiceOption(vs services.VariableService) VariableHandlerOption {
+	return func(vh *VariableHandler) {
+		vh.variableService = vs
+	}
+}
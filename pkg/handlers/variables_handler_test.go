@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestVariableHandler_CreateVariable(t *testing.T) {
+	vr := &repositories.VariableRepositoryMock{}
+	vs := services.NewVariableService(services.WithVariableRepositoryOption(vr))
+	vh := NewVariableHandler(WithVariableServiceOption(vs))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/variables"
+
+	testCases := []struct {
+		name   string
+		body   string
+		setup  func()
+		status int
+		expect string
+	}{
+		{
+			name:   "returns BadRequest when type is unknown",
+			body:   `{"name": "x", "type": "float"}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "request invalid", "details": {"type": "invalid value provided for this field"}}`,
+		},
+		{
+			name:   "returns BadRequest when variable is invalid",
+			body:   `{"name": "age", "type": "integer", "min": 10, "max": 1}`,
+			status: http.StatusBadRequest,
+			expect: `{"error": "Invalid variable provided", "details": "invalid variable: min 10 is greater than max 1"}`,
+		},
+		{
+			name: "returns Conflict when variable already exists",
+			body: `{"name": "x", "type": "boolean"}`,
+			setup: func() {
+				vr.On("CreateVariable", ctx, &repositories.Variable{Name: "x", Type: "boolean"}).
+					Return(nil, repositories.ErrVariableAlreadyExists).
+					Once()
+			},
+			status: http.StatusConflict,
+			expect: `{"error": "Variable already exists"}`,
+		},
+		{
+			name: "creates variable successfully",
+			body: `{"name": "tier", "type": "enum", "enum": [1, 2, 3], "default": 1, "description": "Plan tier"}`,
+			setup: func() {
+				v := &repositories.Variable{Name: "tier", Type: "enum", Enum: []int{1, 2, 3}, Default: intPointer(1), Description: "Plan tier"}
+				vr.On("CreateVariable", ctx, v).Return(v, nil).Once()
+			},
+			status: http.StatusCreated,
+			expect: `{"name": "tier", "type": "enum", "enum": [1, 2, 3], "default": 1, "description": "Plan tier"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			r := gin.Default()
+			r.POST(endpoint, vh.CreateVariable)
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.JSONEq(t, tc.expect, string(respBody))
+		})
+	}
+
+	vr.AssertExpectations(t)
+}
+
+func TestVariableHandler_ListVariables(t *testing.T) {
+	vr := &repositories.VariableRepositoryMock{}
+	vs := services.NewVariableService(services.WithVariableRepositoryOption(vr))
+	vh := NewVariableHandler(WithVariableServiceOption(vs))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+	endpoint := "/variables"
+
+	vr.On("GetAllVariables", ctx).
+		Return([]repositories.Variable{
+			{Name: "age", Type: "integer", Min: intPointer(0)},
+			{Name: "x", Type: "boolean"},
+		}, nil).
+		Once()
+
+	r := gin.Default()
+	r.GET(endpoint, vh.ListVariables)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `[
+		{"name": "age", "type": "integer", "min": 0, "description": ""},
+		{"name": "x", "type": "boolean", "description": ""}
+	]`, string(respBody))
+
+	vr.AssertExpectations(t)
+}
+
+func TestVariableHandler_GetVariable(t *testing.T) {
+	vr := &repositories.VariableRepositoryMock{}
+	vs := services.NewVariableService(services.WithVariableRepositoryOption(vr))
+	vh := NewVariableHandler(WithVariableServiceOption(vs))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	t.Run("returns NotFound when variable doesn't exist", func(t *testing.T) {
+		vr.On("GetVariableByName", ctx, "x").Return(nil, repositories.ErrVariableNotFound).Once()
+
+		r := gin.Default()
+		r.GET("/variables/:name", vh.GetVariable)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/variables/x", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Variable not found"}`, string(respBody))
+	})
+
+	t.Run("returns variable successfully", func(t *testing.T) {
+		vr.On("GetVariableByName", ctx, "x").
+			Return(&repositories.Variable{Name: "x", Type: "boolean", Description: "Flag"}, nil).
+			Once()
+
+		r := gin.Default()
+		r.GET("/variables/:name", vh.GetVariable)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/variables/x", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"name": "x", "type": "boolean", "description": "Flag"}`, string(respBody))
+	})
+
+	vr.AssertExpectations(t)
+}
+
+func TestVariableHandler_UpdateVariable(t *testing.T) {
+	vr := &repositories.VariableRepositoryMock{}
+	vs := services.NewVariableService(services.WithVariableRepositoryOption(vr))
+	vh := NewVariableHandler(WithVariableServiceOption(vs))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	v := &repositories.Variable{Name: "age", Type: "integer", Max: intPointer(130)}
+	vr.On("UpdateVariable", ctx, v).Return(v, nil).Once()
+
+	r := gin.Default()
+	r.PUT("/variables/:name", vh.UpdateVariable)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "/variables/age", strings.NewReader(`{"type": "integer", "max": 130}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"name": "age", "type": "integer", "max": 130, "description": ""}`, string(respBody))
+
+	vr.AssertExpectations(t)
+}
+
+func TestVariableHandler_DeleteVariable(t *testing.T) {
+	vr := &repositories.VariableRepositoryMock{}
+	vs := services.NewVariableService(services.WithVariableRepositoryOption(vr))
+	vh := NewVariableHandler(WithVariableServiceOption(vs))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	vr.On("DeleteVariable", ctx, "x").Return(nil).Once()
+	vr.On("DeleteVariable", ctx, "y").Return(repositories.ErrVariableNotFound).Once()
+
+	r := gin.Default()
+	r.DELETE("/variables/:name", vh.DeleteVariable)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, "/variables/x", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodDelete, "/variables/y", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	vr.AssertExpectations(t)
+}
+
+func TestExpressionHandler_RegisteredVariables(t *testing.T) {
+	er := &repositories.ExpressionRepositoryMock{}
+	vr := &repositories.VariableRepositoryMock{}
+	es := services.NewExpressionService(
+		services.WithExpressionRepositoryOption(er),
+		services.WithVariableRegistryOption(vr),
+	)
+	eh := NewExpressionHandler(WithExpressionServiceOption(es))
+
+	t.Setenv("GIN_MODE", gin.TestMode)
+
+	ctx := context.Background()
+
+	t.Run("rejects expressions using unregistered variables", func(t *testing.T) {
+		vr.On("GetVariablesByName", ctx, []string{"x", "y"}).
+			Return([]repositories.Variable{{Name: "x", Type: "boolean"}}, nil).
+			Once()
+
+		r := gin.Default()
+		r.POST("/expressions", eh.CreateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/expressions", strings.NewReader(`{"expression": "x AND y", "registered_variables": true}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Unregistered variables", "details": "unregistered variable \"y\" in the logical expression \"x AND y\""}`, string(respBody))
+	})
+
+	t.Run("rejects parameters not allowed by the schema", func(t *testing.T) {
+		er.On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{ID: 1, Value: "x AND y"}, nil).
+			Once()
+		vr.On("GetVariablesByName", ctx, []string{"x", "y"}).
+			Return([]repositories.Variable{{Name: "x", Type: "boolean"}}, nil).
+			Once()
+
+		r := gin.Default()
+		r.GET("/evaluate/:id", eh.EvaluateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/evaluate/1?x=2&y=1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "invalid value 2 for the parameter \"x\": is not a boolean, expected 0 or 1"}`, string(respBody))
+	})
+
+	t.Run("rejects every variable without a variable registry", func(t *testing.T) {
+		eh := NewExpressionHandler(WithExpressionServiceOption(services.NewExpressionService(
+			services.WithExpressionRepositoryOption(er),
+		)))
+
+		r := gin.Default()
+		r.POST("/expressions", eh.CreateExpression)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/expressions", strings.NewReader(`{"expression": "x", "registered_variables": true}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		resp := w.Result()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error": "Unregistered variables", "details": "unregistered variable \"x\" in the logical expression \"x\""}`, string(respBody))
+	})
+
+	er.AssertExpectations(t)
+	vr.AssertExpectations(t)
+}
@@ -0,0 +1,305 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+var ErrBudgetExceeded = errors.New("evaluation budget exceeded")
+
+// budgetCheckInterval is how many steps EvalWithBudget takes between
+// checks of its context.
+const budgetCheckInterval = 256
+
+// Kind identifies the type of a node of the expression tree.
+type Kind int
+
+const (
+	KindVariable Kind = iota
+	KindConstant
+	KindNot
+	KindAnd
+	KindOr
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindVariable:
+		return "variable"
+	case KindConstant:
+		return "constant"
+	case KindNot:
+		return "NOT"
+	case KindAnd:
+		return "AND"
+	case KindOr:
+		return "OR"
+	}
+	return "unknown"
+}
+
+// Node is a node of the abstract syntax tree of a logical expression.
+// Variables carry their Name, constants their Value, NOT has exactly one
+// operand and AND/OR have two or more operands.
+type Node struct {
+	Kind     Kind
+	Name     string
+	Value    bool
+	Operands []*Node
+}
+
+// Var returns a variable node.
+func Var(name string) *Node {
+	return &Node{Kind: KindVariable, Name: name}
+}
+
+// Const returns a constant node.
+func Const(value bool) *Node {
+	return &Node{Kind: KindConstant, Value: value}
+}
+
+// Not returns the negation of the operand.
+func Not(operand *Node) *Node {
+	return &Node{Kind: KindNot, Operands: []*Node{operand}}
+}
+
+// And returns the conjunction of the operands, flattening nested conjunctions.
+// A single operand is returned as is.
+func And(operands ...*Node) *Node {
+	return junction(KindAnd, operands)
+}
+
+// Or returns the disjunction of the operands, flattening nested disjunctions.
+// A single operand is returned as is.
+func Or(operands ...*Node) *Node {
+	return junction(KindOr, operands)
+}
+
+func junction(kind Kind, operands []*Node) *Node {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+
+	flat := make([]*Node, 0, len(operands))
+	for _, operand := range operands {
+		if operand.Kind == kind {
+			flat = append(flat, operand.Operands...)
+			continue
+		}
+		flat = append(flat, operand)
+	}
+
+	return &Node{Kind: kind, Operands: flat}
+}
+
+// Variables returns the distinct variable names of the tree in order of
+// first appearance.
+func (n *Node) Variables() []string {
+	seen := map[string]struct{}{}
+	names := []string{}
+
+	n.Walk(func(node *Node) {
+		if node.Kind != KindVariable {
+			return
+		}
+		if _, ok := seen[node.Name]; ok {
+			return
+		}
+		seen[node.Name] = struct{}{}
+		names = append(names, node.Name)
+	})
+
+	return names
+}
+
+// RequiredVariables returns the variables which must be true for the tree
+// to be true, sorted. It's a syntactic approximation: a tree can require
+// more variables than the ones returned, but never fewer.
+func (n *Node) RequiredVariables() []string {
+	required := n.required(true)
+
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// required returns the variables which must be true for the tree to have
+// the given value.
+func (n *Node) required(value bool) map[string]struct{} {
+	switch n.Kind {
+	case KindVariable:
+		if value {
+			return map[string]struct{}{n.Name: {}}
+		}
+	case KindNot:
+		return n.Operands[0].required(!value)
+	case KindAnd, KindOr:
+		// A true AND, or a false OR, needs every operand to have the value,
+		// and the other ones only need one of them to.
+		every := (n.Kind == KindAnd) == value
+
+		var required map[string]struct{}
+		for i, operand := range n.Operands {
+			names := operand.required(value)
+			switch {
+			case i == 0:
+				required = names
+			case every:
+				for name := range names {
+					required[name] = struct{}{}
+				}
+			default:
+				for name := range required {
+					if _, ok := names[name]; !ok {
+						delete(required, name)
+					}
+				}
+			}
+		}
+		return required
+	}
+
+	return map[string]struct{}{}
+}
+
+// Walk calls fn for every node of the tree in pre-order.
+func (n *Node) Walk(fn func(node *Node)) {
+	fn(n)
+	for _, operand := range n.Operands {
+		operand.Walk(fn)
+	}
+}
+
+// Depth returns the number of levels of the tree. A single node is one
+// level deep.
+func (n *Node) Depth() int {
+	depth := 0
+	for _, operand := range n.Operands {
+		if d := operand.Depth(); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
+
+// Size returns the number of nodes of the tree.
+func (n *Node) Size() int {
+	size := 0
+	n.Walk(func(*Node) {
+		size++
+	})
+	return size
+}
+
+// Eval evaluates the tree with the given variable values. Variables absent
+// from values are considered false.
+func (n *Node) Eval(values map[string]bool) bool {
+	switch n.Kind {
+	case KindVariable:
+		return values[n.Name]
+	case KindConstant:
+		return n.Value
+	case KindNot:
+		return !n.Operands[0].Eval(values)
+	case KindAnd:
+		for _, operand := range n.Operands {
+			if !operand.Eval(values) {
+				return false
+			}
+		}
+		return true
+	case KindOr:
+		for _, operand := range n.Operands {
+			if operand.Eval(values) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// EvalWithBudget evaluates the tree like Eval, visiting at most budget
+// nodes. Zero means no budget. The context is checked along the way, so a
+// cancelled evaluation stops with the context error.
+func (n *Node) EvalWithBudget(ctx context.Context, values map[string]bool, budget int) (bool, error) {
+	e := budgetEvaluator{
+		ctx:    ctx,
+		budget: budget,
+		lookup: func(name string) Truth {
+			return TruthOf(values[name])
+		},
+	}
+
+	res, err := e.eval(n)
+	return res == True, err
+}
+
+// EvalKleene evaluates the tree in Kleene's three-valued logic, with the same
+// budget as EvalWithBudget. Variables absent from values are Unknown.
+func (n *Node) EvalKleene(ctx context.Context, values map[string]Truth, budget int) (Truth, error) {
+	e := budgetEvaluator{
+		ctx:    ctx,
+		budget: budget,
+		lookup: func(name string) Truth {
+			if value, ok := values[name]; ok {
+				return value
+			}
+			return Unknown
+		},
+	}
+
+	return e.eval(n)
+}
+
+type budgetEvaluator struct {
+	ctx    context.Context
+	lookup func(name string) Truth
+	budget int
+	steps  int
+}
+
+func (e *budgetEvaluator) eval(n *Node) (Truth, error) {
+	e.steps++
+	if e.budget > 0 && e.steps > e.budget {
+		return Unknown, ErrBudgetExceeded
+	}
+	if e.steps%budgetCheckInterval == 1 {
+		if err := e.ctx.Err(); err != nil {
+			return Unknown, err
+		}
+	}
+
+	switch n.Kind {
+	case KindVariable:
+		return e.lookup(n.Name), nil
+	case KindConstant:
+		return TruthOf(n.Value), nil
+	case KindNot:
+		value, err := e.eval(n.Operands[0])
+		return value.Not(), err
+	}
+
+	// AND stops at the first false operand and OR at the first true one.
+	// Otherwise, any unknown operand makes the result unknown.
+	stop := TruthOf(n.Kind == KindOr)
+	res := stop.Not()
+	for _, operand := range n.Operands {
+		value, err := e.eval(operand)
+		if err != nil {
+			return Unknown, err
+		}
+		if value == stop {
+			return stop, nil
+		}
+		if value == Unknown {
+			res = Unknown
+		}
+	}
+	return res, nil
+}
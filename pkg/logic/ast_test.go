@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode_Variables(t *testing.T) {
+	node := Or(And(Var("y"), Var("x")), Not(Var("y")), Var("z"))
+
+	assert.Equal(t, []string{"y", "x", "z"}, node.Variables())
+	assert.Equal(t, []string{}, Const(true).Variables())
+}
+
+func TestNode_RequiredVariables(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expect     []string
+	}{
+		{expression: "x", expect: []string{"x"}},
+		{expression: "NOT x", expect: []string{}},
+		{expression: "x AND (y OR NOT z) AND w", expect: []string{"w", "x"}},
+		{expression: "(x AND y) OR (x AND z)", expect: []string{"x"}},
+		{expression: "x OR y", expect: []string{}},
+		{expression: "NOT (NOT x OR NOT y)", expect: []string{"x", "y"}},
+		{expression: "NOT (x AND y) AND z", expect: []string{"z"}},
+		{expression: "x AND y AND x", expect: []string{"x", "y"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			node, err := Parse(tc.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, node.RequiredVariables())
+		})
+	}
+}
+
+func TestNode_Eval(t *testing.T) {
+	node := Or(And(Var("x"), Var("y")), Not(Var("z")))
+
+	assert.True(t, node.Eval(map[string]bool{"x": true, "y": true, "z": true}))
+	assert.False(t, node.Eval(map[string]bool{"x": true, "y": false, "z": true}))
+	assert.True(t, node.Eval(map[string]bool{}))
+	assert.False(t, Const(false).Eval(nil))
+}
+
+func TestNode_DepthAndSize(t *testing.T) {
+	node, err := Parse("x AND (y OR NOT z) AND w")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, node.Depth())
+	assert.Equal(t, 7, node.Size())
+	assert.Equal(t, 1, Var("x").Depth())
+}
+
+func TestNode_EvalWithBudget(t *testing.T) {
+	node, err := Parse("x AND (y OR NOT z) AND w")
+	require.NoError(t, err)
+
+	values := map[string]bool{"x": true, "y": false, "z": false, "w": true}
+
+	t.Run("evaluates within the budget", func(t *testing.T) {
+		res, err := node.EvalWithBudget(context.Background(), values, 7)
+		require.NoError(t, err)
+		assert.True(t, res)
+
+		res, err = node.EvalWithBudget(context.Background(), values, 0)
+		require.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("short-circuits", func(t *testing.T) {
+		res, err := node.EvalWithBudget(context.Background(), map[string]bool{"x": false}, 2)
+		require.NoError(t, err)
+		assert.False(t, res)
+	})
+
+	t.Run("returns error when the budget is exceeded", func(t *testing.T) {
+		res, err := node.EvalWithBudget(context.Background(), values, 6)
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+		assert.False(t, res)
+	})
+
+	t.Run("returns error when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res, err := node.EvalWithBudget(ctx, values, 0)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, res)
+	})
+}
+
+func TestNode_EvalKleene(t *testing.T) {
+	testCases := []struct {
+		expression string
+		values     map[string]Truth
+		expect     Truth
+	}{
+		{expression: "x AND y", values: map[string]Truth{"x": False}, expect: False},
+		{expression: "x AND y", values: map[string]Truth{"x": True}, expect: Unknown},
+		{expression: "x OR y", values: map[string]Truth{"y": True}, expect: True},
+		{expression: "x OR y", values: map[string]Truth{"y": False}, expect: Unknown},
+		{expression: "NOT x", values: map[string]Truth{}, expect: Unknown},
+		{expression: "x OR NOT x", values: map[string]Truth{}, expect: Unknown},
+		{expression: "x AND (y OR NOT z)", values: map[string]Truth{"x": True, "y": False, "z": False}, expect: True},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			node, err := Parse(tc.expression)
+			require.NoError(t, err)
+
+			res, err := node.EvalKleene(context.Background(), tc.values, 0)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, res)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package logic
+
+// BitLanes is the number of assignments a BitProgram evaluates at once.
+const BitLanes = 64
+
+// BitProgram evaluates a tree against 64 assignments at once. The values
+// of each variable are packed into a uint64, bit i holding its value in
+// the assignment i, and the tree is run with bitwise operations.
+type BitProgram struct {
+	variables []string
+	code      []bitInstruction
+	// depth is the size of the stack needed to run the code.
+	depth int
+}
+
+// bitInstruction is an instruction of a BitProgram, run on a stack of
+// words. Variables push the word of the variable at index arg, and AND/OR
+// replace the arg words on top of the stack by their combination.
+type bitInstruction struct {
+	kind  Kind
+	arg   int
+	value bool
+}
+
+// CompileBits compiles the tree into a BitProgram.
+func CompileBits(n *Node) *BitProgram {
+	p := &BitProgram{variables: n.Variables()}
+
+	indexes := make(map[string]int, len(p.variables))
+	for i, name := range p.variables {
+		indexes[name] = i
+	}
+
+	p.compile(n, indexes, 0)
+
+	return p
+}
+
+// compile appends the code of the tree, whose result is pushed on a stack
+// of the given height.
+func (p *BitProgram) compile(n *Node, indexes map[string]int, height int) {
+	if height+1 > p.depth {
+		p.depth = height + 1
+	}
+
+	switch n.Kind {
+	case KindVariable:
+		p.code = append(p.code, bitInstruction{kind: KindVariable, arg: indexes[n.Name]})
+	case KindConstant:
+		p.code = append(p.code, bitInstruction{kind: KindConstant, value: n.Value})
+	case KindNot:
+		p.compile(n.Operands[0], indexes, height)
+		p.code = append(p.code, bitInstruction{kind: KindNot})
+	case KindAnd, KindOr:
+		for i, operand := range n.Operands {
+			p.compile(operand, indexes, height+i)
+		}
+		p.code = append(p.code, bitInstruction{kind: n.Kind, arg: len(n.Operands)})
+	}
+}
+
+// Variables returns the variables of the program, in the order their words
+// are given to Eval.
+func (p *BitProgram) Variables() []string {
+	return p.variables
+}
+
+// Eval returns the values of the tree for the 64 assignments of the words,
+// one per variable in the order of Variables.
+func (p *BitProgram) Eval(words []uint64) uint64 {
+	stack := make([]uint64, 0, p.depth)
+
+	for _, in := range p.code {
+		switch in.kind {
+		case KindVariable:
+			stack = append(stack, words[in.arg])
+		case KindConstant:
+			var word uint64
+			if in.value {
+				word = ^word
+			}
+			stack = append(stack, word)
+		case KindNot:
+			stack[len(stack)-1] = ^stack[len(stack)-1]
+		case KindAnd, KindOr:
+			operands := stack[len(stack)-in.arg:]
+			word := operands[0]
+			for _, operand := range operands[1:] {
+				if in.kind == KindAnd {
+					word &= operand
+				} else {
+					word |= operand
+				}
+			}
+			stack = append(stack[:len(stack)-in.arg], word)
+		}
+	}
+
+	return stack[0]
+}
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitProgram_Eval(t *testing.T) {
+	node, err := Parse("x AND (y OR NOT z)")
+	require.NoError(t, err)
+
+	p := CompileBits(node)
+	require.Equal(t, []string{"x", "y", "z"}, p.Variables())
+
+	// The lanes are the 8 assignments of x, y and z, x being the low bit.
+	x := uint64(0b10101010)
+	y := uint64(0b11001100)
+	z := uint64(0b11110000)
+
+	assert.Equal(t, uint64(0b10001010), p.Eval([]uint64{x, y, z})&0xff)
+	assert.Equal(t, ^uint64(0), CompileBits(Or(Const(true), Var("x"))).Eval([]uint64{0}))
+}
+
+// randomNode returns a random tree of the variables, at most depth
+// operators deep.
+func randomNode(r *rand.Rand, depth int, variables []string) *Node {
+	if depth == 0 || r.Intn(4) == 0 {
+		if r.Intn(10) == 0 {
+			return Const(r.Intn(2) == 0)
+		}
+		return Var(variables[r.Intn(len(variables))])
+	}
+
+	switch r.Intn(3) {
+	case 0:
+		return Not(randomNode(r, depth-1, variables))
+	case 1:
+		return And(randomNode(r, depth-1, variables), randomNode(r, depth-1, variables), randomNode(r, depth-1, variables))
+	default:
+		return Or(randomNode(r, depth-1, variables), randomNode(r, depth-1, variables))
+	}
+}
+
+func TestBitProgram_EvalMatchesEval(t *testing.T) {
+	variables := []string{"a", "b", "c", "d", "e", "f"}
+
+	property := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+
+		node := randomNode(r, 5, variables)
+		p := CompileBits(node)
+
+		words := make([]uint64, len(p.Variables()))
+		for i := range words {
+			words[i] = r.Uint64()
+		}
+
+		res := p.Eval(words)
+
+		for lane := 0; lane < BitLanes; lane++ {
+			values := map[string]bool{}
+			for i, name := range p.Variables() {
+				values[name] = words[i]>>lane&1 == 1
+			}
+
+			if node.Eval(values) != (res>>lane&1 == 1) {
+				t.Logf("%s differs on %v", Format(node, FormatOptions{}), values)
+				return false
+			}
+		}
+
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
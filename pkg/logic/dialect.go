@@ -0,0 +1,243 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownDialect       = errors.New("unknown dialect")
+	ErrConflictingOperators = errors.New("conflicting operators")
+)
+
+// Dialect is a surface syntax accepted by the parser. Every dialect produces
+// the same tree, and the canonical dialect is the one expressions are stored in.
+type Dialect string
+
+const (
+	// DialectCanonical is the stored syntax: x AND (y OR NOT z).
+	DialectCanonical Dialect = "canonical"
+	// DialectC is the C-style syntax: x && (y || !z).
+	DialectC Dialect = "c"
+	// DialectLowercase uses lowercase keywords: x and (y or not z).
+	DialectLowercase Dialect = "lowercase"
+	// DialectUnicode uses the logic symbols: x ∧ (y ∨ ¬z).
+	DialectUnicode Dialect = "unicode"
+	// DialectMixed mixes the keywords of the canonical or lowercase dialect
+	// with the symbols of the C-style or Unicode one: x AND (y || !z). The
+	// keywords and the symbols are each read in the dialect detected for
+	// them, so it's only ever detected, never requested.
+	DialectMixed Dialect = "mixed"
+)
+
+// Dialects lists every supported dialect.
+var Dialects = []Dialect{DialectCanonical, DialectC, DialectLowercase, DialectUnicode}
+
+// dialectSpec maps the keywords and symbols of a dialect to tokens.
+type dialectSpec struct {
+	words   map[string]tokenKind
+	symbols map[string]tokenKind
+}
+
+var dialectSpecs = map[Dialect]dialectSpec{
+	DialectCanonical: {
+		words: map[string]tokenKind{
+			"AND":   tokenAnd,
+			"OR":    tokenOr,
+			"NOT":   tokenNot,
+			"TRUE":  tokenTrue,
+			"FALSE": tokenFalse,
+		},
+	},
+	DialectC: {
+		words: map[string]tokenKind{
+			"true":  tokenTrue,
+			"false": tokenFalse,
+		},
+		symbols: map[string]tokenKind{
+			"&&": tokenAnd,
+			"||": tokenOr,
+			"!":  tokenNot,
+		},
+	},
+	DialectLowercase: {
+		words: map[string]tokenKind{
+			"and":   tokenAnd,
+			"or":    tokenOr,
+			"not":   tokenNot,
+			"true":  tokenTrue,
+			"false": tokenFalse,
+		},
+	},
+	DialectUnicode: {
+		symbols: map[string]tokenKind{
+			"∧": tokenAnd,
+			"∨": tokenOr,
+			"¬": tokenNot,
+			"⊤": tokenTrue,
+			"⊥": tokenFalse,
+		},
+	},
+}
+
+// canonicalText is how each token is written in the canonical dialect.
+var canonicalText = map[tokenKind]string{
+	tokenAnd:    "AND",
+	tokenOr:     "OR",
+	tokenNot:    "NOT",
+	tokenTrue:   "TRUE",
+	tokenFalse:  "FALSE",
+	tokenLParen: "(",
+	tokenRParen: ")",
+}
+
+var (
+	wordRegex           = regexp.MustCompile(`[A-Za-z]+`)
+	lowercaseKeywords   = map[string]struct{}{"and": {}, "or": {}, "not": {}}
+	canonicalKeywords   = map[string]struct{}{"AND": {}, "OR": {}, "NOT": {}, "TRUE": {}, "FALSE": {}}
+	unicodeSymbols      = "∧∨¬⊤⊥"
+	cStyleSymbolPattern = []string{"&&", "||", "!"}
+)
+
+// LookupDialect returns the dialect with the given name. An empty name
+// returns an empty dialect, meaning it must be detected.
+func LookupDialect(name string) (Dialect, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	for _, d := range Dialects {
+		if string(d) == name {
+			return d, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownDialect, name)
+}
+
+// DetectDialect guesses the dialect a logical expression was written in.
+// Expressions without any dialect-specific token are considered canonical,
+// and the ones using both keyword and symbol operators are mixed. It fails
+// with ErrConflictingOperators when the symbols of the C-style and Unicode
+// dialects are mixed, naming one of each.
+func DetectDialect(logicalExpression string) (Dialect, error) {
+	words, symbols, err := detectOperators(logicalExpression)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case words != "" && symbols != "":
+		return DialectMixed, nil
+	case symbols != "":
+		return symbols, nil
+	case words != "":
+		return words, nil
+	}
+
+	return DialectCanonical, nil
+}
+
+// detectOperators returns the dialect of the keyword operators of the
+// expression and the dialect of its symbol operators, each empty when the
+// expression has none.
+func detectOperators(logicalExpression string) (words, symbols Dialect, err error) {
+	var cSymbol string
+	for _, symbol := range cStyleSymbolPattern {
+		if strings.Contains(logicalExpression, symbol) {
+			cSymbol = symbol
+			break
+		}
+	}
+
+	if i := strings.IndexAny(logicalExpression, unicodeSymbols); i >= 0 {
+		if cSymbol != "" {
+			r, _ := utf8.DecodeRuneInString(logicalExpression[i:])
+			return "", "", fmt.Errorf("%w: %q and %q", ErrConflictingOperators, cSymbol, string(r))
+		}
+		symbols = DialectUnicode
+	} else if cSymbol != "" {
+		symbols = DialectC
+	}
+
+	var hasLowercase, hasCanonical bool
+	for _, word := range wordRegex.FindAllString(logicalExpression, -1) {
+		if _, ok := lowercaseKeywords[word]; ok {
+			hasLowercase = true
+		}
+		if _, ok := canonicalKeywords[word]; ok {
+			hasCanonical = true
+		}
+	}
+
+	switch {
+	case hasCanonical:
+		words = DialectCanonical
+	case hasLowercase:
+		words = DialectLowercase
+	}
+
+	return words, symbols, nil
+}
+
+// mixedSpec returns the spec reading the keywords and the symbols of the
+// expression in the dialects detected for each of them.
+func mixedSpec(logicalExpression string) (dialectSpec, error) {
+	words, symbols, err := detectOperators(logicalExpression)
+	if err != nil {
+		return dialectSpec{}, err
+	}
+	if words == "" {
+		words = DialectCanonical
+	}
+
+	spec := dialectSpec{
+		words:   map[string]tokenKind{},
+		symbols: dialectSpecs[symbols].symbols,
+	}
+	for _, d := range []Dialect{words, symbols} {
+		for word, kind := range dialectSpecs[d].words {
+			spec.words[word] = kind
+		}
+	}
+
+	return spec, nil
+}
+
+// Normalize rewrites a logical expression written in the given dialect into
+// the canonical dialect, keeping its parentheses. An empty dialect is detected.
+func Normalize(logicalExpression string, dialect Dialect) (string, error) {
+	if dialect == "" {
+		var err error
+		if dialect, err = DetectDialect(logicalExpression); err != nil {
+			return "", err
+		}
+	}
+
+	tokens, err := tokenize(logicalExpression, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, tok := range tokens {
+		if tok.kind == tokenEOF {
+			break
+		}
+
+		if i > 0 && tokens[i-1].kind != tokenLParen && tok.kind != tokenRParen {
+			sb.WriteByte(' ')
+		}
+
+		if tok.kind == tokenIdent {
+			sb.WriteString(tok.text)
+			continue
+		}
+		sb.WriteString(canonicalText[tok.kind])
+	}
+
+	return sb.String(), nil
+}
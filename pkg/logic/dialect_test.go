@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupDialect(t *testing.T) {
+	d, err := LookupDialect("unicode")
+	require.NoError(t, err)
+	assert.Equal(t, DialectUnicode, d)
+
+	d, err = LookupDialect("")
+	require.NoError(t, err)
+	assert.Equal(t, Dialect(""), d)
+
+	_, err = LookupDialect("pascal")
+	assert.EqualError(t, err, `unknown dialect: "pascal"`)
+}
+
+func TestDetectDialect(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expect     Dialect
+	}{
+		{
+			expression: "x AND y",
+			expect:     DialectCanonical,
+		},
+		{
+			expression: "x",
+			expect:     DialectCanonical,
+		},
+		{
+			expression: "x && !y",
+			expect:     DialectC,
+		},
+		{
+			expression: "x and not y",
+			expect:     DialectLowercase,
+		},
+		{
+			expression: "x ∧ y",
+			expect:     DialectUnicode,
+		},
+		{
+			expression: "x AND and",
+			expect:     DialectCanonical,
+		},
+		{
+			expression: "x AND !y",
+			expect:     DialectMixed,
+		},
+		{
+			expression: "x or y ∧ ¬z",
+			expect:     DialectMixed,
+		},
+	}
+
+	for _, tc := range testCases {
+		d, err := DetectDialect(tc.expression)
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, tc.expect, d, tc.expression)
+	}
+
+	_, err := DetectDialect("x && y ∨ z")
+	assert.ErrorIs(t, err, ErrConflictingOperators)
+	assert.EqualError(t, err, `conflicting operators: "&&" and "∨"`)
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		expression string
+		dialect    Dialect
+		expect     string
+	}{
+		{
+			expression: "(x AND z)",
+			expect:     "(x AND z)",
+		},
+		{
+			expression: "x && y || !z",
+			expect:     "x AND y OR NOT z",
+		},
+		{
+			expression: "x and (y or not z)",
+			expect:     "x AND (y OR NOT z)",
+		},
+		{
+			expression: "x ∧ y ∨ ¬z",
+			expect:     "x AND y OR NOT z",
+		},
+		{
+			expression: "!(x&&true)",
+			dialect:    DialectC,
+			expect:     "NOT (x AND TRUE)",
+		},
+		{
+			expression: "  x   AND\ty ",
+			dialect:    DialectCanonical,
+			expect:     "x AND y",
+		},
+		{
+			expression: "x AND !y || (z && TRUE)",
+			expect:     "x AND NOT y OR (z AND TRUE)",
+		},
+		{
+			expression: "x and ¬y",
+			dialect:    DialectMixed,
+			expect:     "x AND NOT y",
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := Normalize(tc.expression, tc.dialect)
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, tc.expect, got, tc.expression)
+	}
+
+	_, err := Normalize("x && y", DialectLowercase)
+	assert.EqualError(t, err, `syntax error at position 2: unexpected character '&'`)
+
+	_, err = Normalize("x AND !y", DialectCanonical)
+	assert.EqualError(t, err, `syntax error at position 6: unexpected character '!'`)
+
+	_, err = Normalize("x && ¬y", "")
+	assert.ErrorIs(t, err, ErrConflictingOperators)
+}
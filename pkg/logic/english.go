@@ -0,0 +1,128 @@
+package logic
+
+import "strings"
+
+// EnglishOptions configures English.
+type EnglishOptions struct {
+	// Labels are display names for the variables. Variables without a label
+	// are written by name.
+	Labels map[string]string
+}
+
+// English renders the tree as an English sentence, for instance
+// "x AND (y OR z) AND NOT w" reads "x, and either y or z (but not w)".
+// Nested lists are introduced by "both" and "either" to keep their grouping.
+func English(n *Node, opts EnglishOptions) string {
+	e := englishRenderer{labels: opts.Labels}
+	return e.render(n, nil)
+}
+
+type englishRenderer struct {
+	labels map[string]string
+}
+
+func (e englishRenderer) label(name string) string {
+	if label, ok := e.labels[name]; ok && label != "" {
+		return label
+	}
+	return name
+}
+
+// render writes the node as an operand of parent, nil at the top level.
+func (e englishRenderer) render(n *Node, parent *Node) string {
+	switch n.Kind {
+	case KindVariable:
+		return e.label(n.Name)
+	case KindConstant:
+		if n.Value {
+			return "true"
+		}
+		return "false"
+	case KindNot:
+		return e.negation(n.Operands[0])
+	case KindAnd:
+		return e.and(n, parent)
+	}
+	return e.or(n, parent)
+}
+
+func (e englishRenderer) negation(operand *Node) string {
+	switch operand.Kind {
+	case KindAnd:
+		return "not both " + e.list(e.items(operand.Operands, operand), "and")
+	case KindOr:
+		return "neither " + e.list(e.items(operand.Operands, operand), "nor")
+	}
+	return "not " + e.render(operand, nil)
+}
+
+func (e englishRenderer) and(n *Node, parent *Node) string {
+	// Negated variables read better as an exception to the positive
+	// operands, when there are some.
+	var positives, negatives []*Node
+	for _, operand := range n.Operands {
+		if operand.Kind == KindNot && operand.Operands[0].Kind == KindVariable {
+			negatives = append(negatives, operand.Operands[0])
+			continue
+		}
+		positives = append(positives, operand)
+	}
+
+	if len(positives) == 0 {
+		return "neither " + e.list(e.items(negatives, n), "nor")
+	}
+
+	var sb strings.Builder
+	if parent != nil {
+		sb.WriteString("both ")
+	}
+	sb.WriteString(e.list(e.items(positives, n), "and"))
+
+	switch len(negatives) {
+	case 0:
+	case 1:
+		sb.WriteString(" (but not " + e.render(negatives[0], n) + ")")
+	default:
+		sb.WriteString(" (but neither " + e.list(e.items(negatives, n), "nor") + ")")
+	}
+
+	return sb.String()
+}
+
+func (e englishRenderer) or(n *Node, parent *Node) string {
+	text := e.list(e.items(n.Operands, n), "or")
+	if parent != nil || len(n.Operands) > 2 {
+		return "either " + text
+	}
+	return text
+}
+
+func (e englishRenderer) items(operands []*Node, parent *Node) []string {
+	items := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		items = append(items, e.render(operand, parent))
+	}
+	return items
+}
+
+// list joins the items with the conjunction. A comma separates the items
+// when there are more than two of them or when they are phrases themselves.
+func (e englishRenderer) list(items []string, conjunction string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+
+	compound := len(items) > 2
+	for _, item := range items {
+		if strings.Contains(item, " ") {
+			compound = true
+		}
+	}
+
+	if !compound {
+		return items[0] + " " + conjunction + " " + items[1]
+	}
+
+	last := len(items) - 1
+	return strings.Join(items[:last], ", ") + ", " + conjunction + " " + items[last]
+}
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnglish(t *testing.T) {
+	testCases := []struct {
+		expression string
+		labels     map[string]string
+		expect     string
+	}{
+		{expression: "x", expect: "x"},
+		{expression: "TRUE", expect: "true"},
+		{expression: "NOT x", expect: "not x"},
+		{expression: "x AND y", expect: "x and y"},
+		{expression: "x OR y OR z", expect: "either x, y, or z"},
+		{expression: "x AND (y OR z) AND NOT w", expect: "x, and either y or z (but not w)"},
+		{expression: "x AND NOT y AND NOT z", expect: "x (but neither y nor z)"},
+		{expression: "NOT x AND NOT y", expect: "neither x nor y"},
+		{expression: "NOT (x OR y)", expect: "neither x nor y"},
+		{expression: "NOT (x AND y)", expect: "not both x and y"},
+		{expression: "x AND y OR z", expect: "both x and y, or z"},
+		{expression: "(x OR y) AND (z OR w)", expect: "either x or y, and either z or w"},
+		{
+			expression: "x AND NOT y",
+			labels:     map[string]string{"x": "the user is active", "y": "the account is locked"},
+			expect:     "the user is active (but not the account is locked)",
+		},
+		{
+			expression: "x OR y",
+			labels:     map[string]string{"x": "premium"},
+			expect:     "premium or y",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			node, err := Parse(tc.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, English(node, EnglishOptions{Labels: tc.labels}))
+		})
+	}
+}
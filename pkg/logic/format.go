@@ -0,0 +1,101 @@
+package logic
+
+import "strings"
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// Width is the maximum line length. Expressions longer than it are broken
+	// before their top-level operators. Zero disables wrapping.
+	Width int
+}
+
+// indentWidth is how much the content of a wrapped parenthesis is indented.
+const indentWidth = 2
+
+// precedence returns how tightly a node binds. Atoms bind the tightest.
+func precedence(n *Node) int {
+	switch n.Kind {
+	case KindOr:
+		return 1
+	case KindAnd:
+		return 2
+	case KindNot:
+		return 3
+	}
+	return 4
+}
+
+// Format prints the tree in the canonical dialect with single spaces between
+// tokens and only the parentheses required by the precedence rules.
+func Format(n *Node, opts FormatOptions) string {
+	f := formatter{width: opts.Width}
+	return f.format(n, 0)
+}
+
+// String prints the tree in the canonical dialect on a single line.
+func (n *Node) String() string {
+	return Format(n, FormatOptions{})
+}
+
+type formatter struct {
+	width int
+}
+
+func (f formatter) fits(indent int, text string) bool {
+	return f.width <= 0 || indent+len(text) <= f.width
+}
+
+func (f formatter) format(n *Node, indent int) string {
+	switch n.Kind {
+	case KindVariable:
+		return n.Name
+	case KindConstant:
+		if n.Value {
+			return "TRUE"
+		}
+		return "FALSE"
+	case KindNot:
+		return "NOT " + f.operand(n.Operands[0], n, indent+len("NOT "))
+	}
+
+	flat := formatter{}.junction(n, indent)
+	if f.fits(indent, flat) {
+		return flat
+	}
+
+	return f.junction(n, indent)
+}
+
+// junction prints an AND/OR node, one operand per line when wrapping.
+func (f formatter) junction(n *Node, indent int) string {
+	separator := " " + n.Kind.String() + " "
+	if f.width > 0 {
+		separator = "\n" + strings.Repeat(" ", indent) + n.Kind.String() + " "
+	}
+
+	parts := make([]string, 0, len(n.Operands))
+	for i, operand := range n.Operands {
+		operandIndent := indent
+		if i > 0 {
+			operandIndent += len(n.Kind.String()) + 1
+		}
+		parts = append(parts, f.operand(operand, n, operandIndent))
+	}
+
+	return strings.Join(parts, separator)
+}
+
+// operand prints a child of parent, parenthesized when it binds looser.
+func (f formatter) operand(child, parent *Node, indent int) string {
+	if precedence(child) >= precedence(parent) {
+		return f.format(child, indent)
+	}
+
+	flat := "(" + formatter{}.format(child, indent+1) + ")"
+	if f.fits(indent, flat) {
+		return flat
+	}
+
+	inner := indent + indentWidth
+	return "(\n" + strings.Repeat(" ", inner) + f.format(child, inner) + "\n" + strings.Repeat(" ", indent) + ")"
+}
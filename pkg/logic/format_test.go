@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expect     string
+	}{
+		{
+			expression: "((x))",
+			expect:     "x",
+		},
+		{
+			expression: "(x AND z)",
+			expect:     "x AND z",
+		},
+		{
+			expression: "(x AND y) OR z",
+			expect:     "x AND y OR z",
+		},
+		{
+			expression: "x AND (y OR z)",
+			expect:     "x AND (y OR z)",
+		},
+		{
+			expression: "((x OR y) AND (z OR k) OR j)",
+			expect:     "(x OR y) AND (z OR k) OR j",
+		},
+		{
+			expression: "x   OR (y OR  (z))",
+			expect:     "x OR y OR z",
+		},
+		{
+			expression: "NOT (NOT x) AND NOT (y OR TRUE)",
+			expect:     "NOT NOT x AND NOT (y OR TRUE)",
+		},
+	}
+
+	for _, tc := range testCases {
+		node, err := Parse(tc.expression)
+		require.NoError(t, err, tc.expression)
+
+		got := Format(node, FormatOptions{})
+		assert.Equal(t, tc.expect, got, tc.expression)
+
+		// The output must parse back into the same tree.
+		reparsed, err := Parse(got)
+		require.NoError(t, err, got)
+		assert.Equal(t, node, reparsed, got)
+	}
+}
+
+func TestFormat_Width(t *testing.T) {
+	node, err := Parse("(alpha AND beta) OR (gamma AND (delta OR epsilon OR zeta)) OR NOT eta")
+	require.NoError(t, err)
+
+	got := Format(node, FormatOptions{Width: 34})
+
+	expect := "alpha AND beta\n" +
+		"OR gamma\n" +
+		"   AND (delta OR epsilon OR zeta)\n" +
+		"OR NOT eta"
+	assert.Equal(t, expect, got)
+
+	got = Format(node, FormatOptions{Width: 30})
+
+	expect = "alpha AND beta\n" +
+		"OR gamma\n" +
+		"   AND (\n" +
+		"         delta\n" +
+		"         OR epsilon\n" +
+		"         OR zeta\n" +
+		"       )\n" +
+		"OR NOT eta"
+	assert.Equal(t, expect, got)
+
+	reparsed, err := Parse(got)
+	require.NoError(t, err)
+	assert.Equal(t, node, reparsed)
+
+	assert.Equal(t, "alpha AND beta OR gamma AND (delta OR epsilon OR zeta) OR NOT eta", Format(node, FormatOptions{Width: 80}))
+}
@@ -0,0 +1,114 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"strings"
+)
+
+var ErrInvalidGoIdentifier = errors.New("invalid Go identifier")
+
+// GoOptions configures ToGo.
+type GoOptions struct {
+	// Package is the package clause of the file. Defaults to "rules".
+	Package string
+	// Function names the generated function. Defaults to "Rule".
+	Function string
+}
+
+// goReserved are the Go keywords and predeclared identifiers made of
+// lowercase letters only, which variables could be named after.
+var goReserved = map[string]struct{}{
+	"break": {}, "case": {}, "chan": {}, "const": {}, "continue": {}, "default": {}, "defer": {},
+	"else": {}, "fallthrough": {}, "for": {}, "func": {}, "go": {}, "goto": {}, "if": {},
+	"import": {}, "interface": {}, "map": {}, "package": {}, "range": {}, "return": {},
+	"select": {}, "struct": {}, "switch": {}, "type": {}, "var": {},
+	"any": {}, "append": {}, "bool": {}, "byte": {}, "cap": {}, "clear": {}, "close": {},
+	"complex": {}, "copy": {}, "delete": {}, "error": {}, "false": {}, "imag": {}, "int": {},
+	"iota": {}, "len": {}, "make": {}, "max": {}, "min": {}, "new": {}, "nil": {}, "panic": {},
+	"print": {}, "println": {}, "real": {}, "recover": {}, "rune": {}, "string": {}, "true": {},
+	"uint": {}, "uintptr": {},
+}
+
+// goIdentifier renames variables clashing with a reserved word. Variables
+// can't contain underscores, so the renamed ones can't clash either.
+func goIdentifier(name string) string {
+	if _, ok := goReserved[name]; ok {
+		return name + "_"
+	}
+	return name
+}
+
+// ToGo generates a gofmt-clean Go source file declaring a function that
+// takes one bool parameter per variable, in order of appearance, and
+// returns the value of the expression.
+func ToGo(n *Node, opts GoOptions) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "rules"
+	}
+	if opts.Function == "" {
+		opts.Function = "Rule"
+	}
+
+	for _, name := range []string{opts.Package, opts.Function} {
+		if !gotoken.IsIdentifier(name) || name == "_" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGoIdentifier, name)
+		}
+	}
+
+	parameters := make([]string, 0, len(n.Variables()))
+	for _, name := range n.Variables() {
+		parameters = append(parameters, goIdentifier(name))
+	}
+
+	signature := ""
+	if len(parameters) > 0 {
+		signature = strings.Join(parameters, ", ") + " bool"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by logic-exp. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", opts.Package)
+	fmt.Fprintf(&sb, "// %s evaluates the logical expression %q.\n", opts.Function, n.String())
+	fmt.Fprintf(&sb, "func %s(%s) bool {\n", opts.Function, signature)
+	fmt.Fprintf(&sb, "\treturn %s\n", goExpression(n))
+	sb.WriteString("}\n")
+
+	return format.Source([]byte(sb.String()))
+}
+
+func goExpression(n *Node) string {
+	switch n.Kind {
+	case KindVariable:
+		return goIdentifier(n.Name)
+	case KindConstant:
+		if n.Value {
+			return "true"
+		}
+		return "false"
+	case KindNot:
+		return "!" + goOperand(n.Operands[0], n)
+	}
+
+	operator := " && "
+	if n.Kind == KindOr {
+		operator = " || "
+	}
+
+	parts := make([]string, 0, len(n.Operands))
+	for _, operand := range n.Operands {
+		parts = append(parts, goOperand(operand, n))
+	}
+
+	return strings.Join(parts, operator)
+}
+
+// goOperand uses the same precedence rules as Format, which match Go's.
+func goOperand(child, parent *Node) string {
+	if precedence(child) >= precedence(parent) {
+		return goExpression(child)
+	}
+	return "(" + goExpression(child) + ")"
+}
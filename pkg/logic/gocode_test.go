@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	gotoken "go/token"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToGo(t *testing.T) {
+	t.Run("returns error when a name isn't a Go identifier", func(t *testing.T) {
+		node, err := Parse("x")
+		require.NoError(t, err)
+
+		code, err := ToGo(node, GoOptions{Function: "42"})
+		assert.ErrorIs(t, err, ErrInvalidGoIdentifier)
+		assert.Nil(t, code)
+
+		code, err = ToGo(node, GoOptions{Package: "func"})
+		assert.ErrorIs(t, err, ErrInvalidGoIdentifier)
+		assert.Nil(t, code)
+	})
+
+	t.Run("generates the function", func(t *testing.T) {
+		node, err := Parse("x AND (y OR NOT z)")
+		require.NoError(t, err)
+
+		code, err := ToGo(node, GoOptions{Function: "Rule42"})
+		require.NoError(t, err)
+
+		assert.Equal(t, `// Code generated by logic-exp. DO NOT EDIT.
+
+package rules
+
+// Rule42 evaluates the logical expression "x AND (y OR NOT z)".
+func Rule42(x, y, z bool) bool {
+	return x && (y || !z)
+}
+`, string(code))
+	})
+
+	t.Run("renames reserved words", func(t *testing.T) {
+		node, err := Parse("true AND NOT (type OR FALSE)")
+		require.NoError(t, err)
+
+		code, err := ToGo(node, GoOptions{Package: "policy", Function: "Allowed"})
+		require.NoError(t, err)
+
+		assert.Contains(t, string(code), "package policy\n")
+		assert.Contains(t, string(code), "func Allowed(true_, type_ bool) bool {\n\treturn true_ && !(type_ || false)\n}")
+	})
+}
+
+// TestToGo_Interpreter checks the generated functions against
+// EvaluateLogicalExpression on every assignment, by interpreting the
+// returned Go expression.
+func TestToGo_Interpreter(t *testing.T) {
+	expressions := []string{
+		"x",
+		"NOT x",
+		"TRUE",
+		"x AND NOT x",
+		"x OR y OR NOT x",
+		"x AND (y OR NOT z)",
+		"NOT (x AND y) OR (z AND NOT (w OR x))",
+		"(a OR b) AND (c OR NOT d) AND NOT (e AND a)",
+		"NOT (NOT x) AND (func OR NOT y)",
+	}
+
+	for _, expression := range expressions {
+		t.Run(expression, func(t *testing.T) {
+			node, err := Parse(expression)
+			require.NoError(t, err)
+
+			code, err := ToGo(node, GoOptions{})
+			require.NoError(t, err)
+
+			formatted, err := format.Source(code)
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(code), "generated code isn't gofmt-clean")
+
+			body := parseGoFunction(t, code)
+
+			variables := node.Variables()
+			for row := 0; row < 1<<len(variables); row++ {
+				values := make(map[string]bool, len(variables))
+				parameters := make(map[string]int, len(variables))
+				for v, name := range variables {
+					values[goIdentifier(name)] = row&(1<<v) != 0
+					parameters[name] = 0
+					if row&(1<<v) != 0 {
+						parameters[name] = 1
+					}
+				}
+
+				expected, err := utils.EvaluateLogicalExpression(expression, parameters)
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, evalGoExpression(t, body, values), "parameters %v", parameters)
+			}
+		})
+	}
+}
+
+// parseGoFunction returns the expression returned by the single function
+// of the file, checking its signature.
+func parseGoFunction(t *testing.T, code []byte) ast.Expr {
+	t.Helper()
+
+	file, err := goparser.ParseFile(gotoken.NewFileSet(), "rule.go", code, 0)
+	require.NoError(t, err)
+	require.Len(t, file.Decls, 1)
+
+	fn, ok := file.Decls[0].(*ast.FuncDecl)
+	require.True(t, ok)
+
+	for _, field := range fn.Type.Params.List {
+		assert.Equal(t, "bool", field.Type.(*ast.Ident).Name)
+	}
+	require.Len(t, fn.Type.Results.List, 1)
+	assert.Equal(t, "bool", fn.Type.Results.List[0].Type.(*ast.Ident).Name)
+
+	require.Len(t, fn.Body.List, 1)
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	require.True(t, ok)
+	require.Len(t, ret.Results, 1)
+
+	return ret.Results[0]
+}
+
+func evalGoExpression(t *testing.T, expr ast.Expr, values map[string]bool) bool {
+	t.Helper()
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if value, ok := values[e.Name]; ok {
+			return value
+		}
+		require.Contains(t, []string{"true", "false"}, e.Name)
+		return e.Name == "true"
+	case *ast.ParenExpr:
+		return evalGoExpression(t, e.X, values)
+	case *ast.UnaryExpr:
+		require.Equal(t, gotoken.NOT, e.Op)
+		return !evalGoExpression(t, e.X, values)
+	case *ast.BinaryExpr:
+		x, y := evalGoExpression(t, e.X, values), evalGoExpression(t, e.Y, values)
+		switch e.Op {
+		case gotoken.LAND:
+			return x && y
+		case gotoken.LOR:
+			return x || y
+		}
+	}
+
+	require.Failf(t, "unexpected Go expression", "%T", expr)
+	return false
+}
@@ -0,0 +1,182 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownGraphFormat = errors.New("unknown graph format")
+
+// GraphFormat is a text format a tree can be rendered in.
+type GraphFormat string
+
+const (
+	GraphFormatDOT     GraphFormat = "dot"
+	GraphFormatMermaid GraphFormat = "mermaid"
+)
+
+// GraphOptions configures RenderGraph.
+type GraphOptions struct {
+	Format GraphFormat
+	// Values, when set, overlays an evaluation trace on the graph: nodes
+	// evaluating to true are green and nodes evaluating to false are red.
+	Values map[string]bool
+}
+
+// Graph is a directed graph ready to be rendered. Nodes are identified by
+// their index, and Results holds the overlaid value of each node, if any.
+type Graph struct {
+	Labels  []string
+	Edges   []GraphEdge
+	Results []*bool
+}
+
+// GraphEdge links a node to one of its children. Label is optional.
+type GraphEdge struct {
+	From, To int
+	Label    string
+	Dashed   bool
+}
+
+// RenderGraph renders the tree as a DOT or Mermaid graph.
+func RenderGraph(n *Node, opts GraphOptions) (string, error) {
+	return NewGraph(n, opts.Values).Render(opts.Format)
+}
+
+// NewGraph returns the graph of the tree, overlaid with the evaluation trace
+// of values when they are given.
+func NewGraph(n *Node, values map[string]bool) *Graph {
+	g := &Graph{}
+	g.addTree(n, values)
+
+	return g
+}
+
+func (g *Graph) addTree(n *Node, values map[string]bool) int {
+	id := len(g.Labels)
+
+	label := n.Kind.String()
+	switch n.Kind {
+	case KindVariable:
+		label = n.Name
+	case KindConstant:
+		label = "FALSE"
+		if n.Value {
+			label = "TRUE"
+		}
+	}
+
+	g.Labels = append(g.Labels, label)
+	g.Results = append(g.Results, nil)
+	if values != nil {
+		result := n.Eval(values)
+		g.Results[id] = &result
+	}
+
+	for _, operand := range n.Operands {
+		g.Edges = append(g.Edges, GraphEdge{From: id, To: len(g.Labels)})
+		g.addTree(operand, values)
+	}
+
+	return id
+}
+
+// Render prints the graph in the given format.
+func (g *Graph) Render(format GraphFormat) (string, error) {
+	switch format {
+	case GraphFormatDOT:
+		return g.renderDOT(), nil
+	case GraphFormatMermaid:
+		return g.renderMermaid(), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownGraphFormat, format)
+}
+
+func (g *Graph) renderDOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph expression {\n")
+	for id, label := range g.Labels {
+		attributes := fmt.Sprintf("label=%q", label)
+		if result := g.Results[id]; result != nil {
+			color := "red"
+			if *result {
+				color = "green"
+			}
+			attributes += fmt.Sprintf(", color=%s, style=filled, fillcolor=%s", color, color)
+		}
+		fmt.Fprintf(&sb, "  n%d [%s];\n", id, attributes)
+	}
+	for _, edge := range g.Edges {
+		attributes := []string{}
+		if edge.Label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", edge.Label))
+		}
+		if edge.Dashed {
+			attributes = append(attributes, "style=dashed")
+		}
+		if result := g.Results[edge.To]; result != nil {
+			color := "red"
+			if *result {
+				color = "green"
+			}
+			attributes = append(attributes, "color="+color)
+		}
+
+		if len(attributes) == 0 {
+			fmt.Fprintf(&sb, "  n%d -> n%d;\n", edge.From, edge.To)
+			continue
+		}
+		fmt.Fprintf(&sb, "  n%d -> n%d [%s];\n", edge.From, edge.To, strings.Join(attributes, ", "))
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+func (g *Graph) renderMermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("graph TD\n")
+	for id, label := range g.Labels {
+		fmt.Fprintf(&sb, "  n%d[%q]\n", id, label)
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Dashed {
+			arrow = "-.->"
+		}
+
+		if edge.Label != "" {
+			fmt.Fprintf(&sb, "  n%d %s|%s| n%d\n", edge.From, arrow, edge.Label, edge.To)
+			continue
+		}
+		fmt.Fprintf(&sb, "  n%d %s n%d\n", edge.From, arrow, edge.To)
+	}
+
+	var trueNodes, falseNodes []string
+	for id, result := range g.Results {
+		if result == nil {
+			continue
+		}
+		if *result {
+			trueNodes = append(trueNodes, fmt.Sprintf("n%d", id))
+		} else {
+			falseNodes = append(falseNodes, fmt.Sprintf("n%d", id))
+		}
+	}
+	if len(trueNodes) > 0 || len(falseNodes) > 0 {
+		sb.WriteString("  classDef true fill:#9f9,stroke:#080\n")
+		sb.WriteString("  classDef false fill:#f99,stroke:#800\n")
+	}
+	if len(trueNodes) > 0 {
+		fmt.Fprintf(&sb, "  class %s true\n", strings.Join(trueNodes, ","))
+	}
+	if len(falseNodes) > 0 {
+		fmt.Fprintf(&sb, "  class %s false\n", strings.Join(falseNodes, ","))
+	}
+
+	return sb.String()
+}
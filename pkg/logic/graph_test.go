@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderGraph(t *testing.T) {
+	node, err := Parse("x AND NOT y")
+	require.NoError(t, err)
+
+	t.Run("renders DOT", func(t *testing.T) {
+		got, err := RenderGraph(node, GraphOptions{Format: GraphFormatDOT})
+		require.NoError(t, err)
+
+		expect := `digraph expression {
+  n0 [label="AND"];
+  n1 [label="x"];
+  n2 [label="NOT"];
+  n3 [label="y"];
+  n0 -> n1;
+  n0 -> n2;
+  n2 -> n3;
+}
+`
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("renders DOT with an evaluation trace", func(t *testing.T) {
+		got, err := RenderGraph(node, GraphOptions{
+			Format: GraphFormatDOT,
+			Values: map[string]bool{"x": true, "y": true},
+		})
+		require.NoError(t, err)
+
+		expect := `digraph expression {
+  n0 [label="AND", color=red, style=filled, fillcolor=red];
+  n1 [label="x", color=green, style=filled, fillcolor=green];
+  n2 [label="NOT", color=red, style=filled, fillcolor=red];
+  n3 [label="y", color=green, style=filled, fillcolor=green];
+  n0 -> n1 [color=green];
+  n0 -> n2 [color=red];
+  n2 -> n3 [color=green];
+}
+`
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("renders Mermaid", func(t *testing.T) {
+		got, err := RenderGraph(node, GraphOptions{Format: GraphFormatMermaid})
+		require.NoError(t, err)
+
+		expect := `graph TD
+  n0["AND"]
+  n1["x"]
+  n2["NOT"]
+  n3["y"]
+  n0 --> n1
+  n0 --> n2
+  n2 --> n3
+`
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("renders Mermaid with an evaluation trace", func(t *testing.T) {
+		got, err := RenderGraph(node, GraphOptions{
+			Format: GraphFormatMermaid,
+			Values: map[string]bool{"x": true, "y": false},
+		})
+		require.NoError(t, err)
+
+		expect := `graph TD
+  n0["AND"]
+  n1["x"]
+  n2["NOT"]
+  n3["y"]
+  n0 --> n1
+  n0 --> n2
+  n2 --> n3
+  classDef true fill:#9f9,stroke:#080
+  classDef false fill:#f99,stroke:#800
+  class n0,n1,n2 true
+  class n3 false
+`
+		assert.Equal(t, expect, got)
+	})
+
+	t.Run("returns error when the format is unknown", func(t *testing.T) {
+		got, err := RenderGraph(node, GraphOptions{Format: "svg"})
+
+		assert.EqualError(t, err, `unknown graph format: "svg"`)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidJSONLogic = errors.New("invalid JSONLogic")
+
+// ToJSONLogic converts the tree into a JSONLogic document, ready to be
+// marshalled. Variables become {"var": name}, constants become booleans and
+// NOT/AND/OR become the "!", "and" and "or" operations.
+func ToJSONLogic(n *Node) interface{} {
+	switch n.Kind {
+	case KindVariable:
+		return map[string]interface{}{"var": n.Name}
+	case KindConstant:
+		return n.Value
+	case KindNot:
+		return map[string]interface{}{"!": []interface{}{ToJSONLogic(n.Operands[0])}}
+	}
+
+	operands := make([]interface{}, 0, len(n.Operands))
+	for _, operand := range n.Operands {
+		operands = append(operands, ToJSONLogic(operand))
+	}
+
+	if n.Kind == KindAnd {
+		return map[string]interface{}{"and": operands}
+	}
+	return map[string]interface{}{"or": operands}
+}
+
+// FromJSONLogic converts a JSONLogic document into a tree. Only the boolean
+// subset is supported: var, and, or, ! and !!, and boolean literals.
+func FromJSONLogic(document []byte) (*Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var rule interface{}
+	if err := decoder.Decode(&rule); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSONLogic, err.Error())
+	}
+
+	return fromJSONLogic(rule)
+}
+
+func fromJSONLogic(rule interface{}) (*Node, error) {
+	switch rule := rule.(type) {
+	case bool:
+		return Const(rule), nil
+	case map[string]interface{}:
+		if len(rule) != 1 {
+			return nil, fmt.Errorf("%w: operations must have exactly one key", ErrInvalidJSONLogic)
+		}
+
+		for operator, args := range rule {
+			return fromJSONLogicOperation(operator, args)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unsupported value %v", ErrInvalidJSONLogic, rule)
+}
+
+func fromJSONLogicOperation(operator string, args interface{}) (*Node, error) {
+	// JSONLogic allows a single argument without the surrounding array.
+	values, ok := args.([]interface{})
+	if !ok {
+		values = []interface{}{args}
+	}
+
+	switch operator {
+	case "var":
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: var defaults aren't supported", ErrInvalidJSONLogic)
+		}
+
+		name, ok := values[0].(string)
+		if !ok || !isIdentifier(name) {
+			return nil, fmt.Errorf("%w: invalid variable name %v", ErrInvalidJSONLogic, values[0])
+		}
+		return Var(name), nil
+	case "!", "!!":
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: %q expects one argument", ErrInvalidJSONLogic, operator)
+		}
+
+		operand, err := fromJSONLogic(values[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == "!!" {
+			return operand, nil
+		}
+		return Not(operand), nil
+	case "and", "or":
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: %q expects at least one argument", ErrInvalidJSONLogic, operator)
+		}
+
+		operands := make([]*Node, 0, len(values))
+		for _, value := range values {
+			operand, err := fromJSONLogic(value)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+
+		if operator == "and" {
+			return And(operands...), nil
+		}
+		return Or(operands...), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operation %q", ErrInvalidJSONLogic, operator)
+}
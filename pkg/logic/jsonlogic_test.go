@@ -0,0 +1,113 @@
+package logic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSONLogic(t *testing.T) {
+	node, err := Parse("x AND (y OR NOT z) AND TRUE")
+	require.NoError(t, err)
+
+	document, err := json.Marshal(ToJSONLogic(node))
+	require.NoError(t, err)
+
+	expect := `
+		{
+			"and": [
+				{"var": "x"},
+				{"or": [{"var": "y"}, {"!": [{"var": "z"}]}]},
+				true
+			]
+		}
+	`
+	assert.JSONEq(t, expect, string(document))
+}
+
+func TestFromJSONLogic(t *testing.T) {
+	testCases := []struct {
+		document string
+		expect   *Node
+	}{
+		{
+			document: `{"var": "x"}`,
+			expect:   Var("x"),
+		},
+		{
+			document: `{"var": ["x"]}`,
+			expect:   Var("x"),
+		},
+		{
+			document: `{"and": [{"var": "x"}, {"or": [{"var": "y"}, {"!": {"var": "z"}}]}]}`,
+			expect:   And(Var("x"), Or(Var("y"), Not(Var("z")))),
+		},
+		{
+			document: `{"!!": [{"or": [{"var": "x"}, false]}]}`,
+			expect:   Or(Var("x"), Const(false)),
+		},
+		{
+			document: `{"and": [{"var": "x"}]}`,
+			expect:   Var("x"),
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := FromJSONLogic([]byte(tc.document))
+		require.NoError(t, err, tc.document)
+		assert.Equal(t, tc.expect, got, tc.document)
+
+		// Converting back and forth must keep the tree.
+		document, err := json.Marshal(ToJSONLogic(got))
+		require.NoError(t, err)
+
+		roundTrip, err := FromJSONLogic(document)
+		require.NoError(t, err)
+		assert.Equal(t, got, roundTrip)
+	}
+}
+
+func TestFromJSONLogic_Errors(t *testing.T) {
+	testCases := []struct {
+		document string
+		err      string
+	}{
+		{
+			document: `{"and": [`,
+			err:      "invalid JSONLogic: unexpected EOF",
+		},
+		{
+			document: `{"==": [{"var": "x"}, 1]}`,
+			err:      `invalid JSONLogic: unsupported operation "=="`,
+		},
+		{
+			document: `{"var": "x", "and": []}`,
+			err:      "invalid JSONLogic: operations must have exactly one key",
+		},
+		{
+			document: `{"var": "user.id"}`,
+			err:      "invalid JSONLogic: invalid variable name user.id",
+		},
+		{
+			document: `{"var": ["x", true]}`,
+			err:      "invalid JSONLogic: var defaults aren't supported",
+		},
+		{
+			document: `{"or": []}`,
+			err:      `invalid JSONLogic: "or" expects at least one argument`,
+		},
+		{
+			document: `1`,
+			err:      "invalid JSONLogic: unsupported value 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := FromJSONLogic([]byte(tc.document))
+		assert.EqualError(t, err, tc.err, tc.document)
+		assert.ErrorIs(t, err, ErrInvalidJSONLogic)
+		assert.Nil(t, got)
+	}
+}
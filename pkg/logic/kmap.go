@@ -0,0 +1,188 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrTooManyVariables = errors.New("too many variables")
+
+// MaxKarnaughVariables is the largest number of variables NewKarnaughMap
+// lays out.
+const MaxKarnaughVariables = 6
+
+// KarnaughMap is the truth table of an expression laid out as a grid whose
+// rows and columns follow the Gray code, so adjacent cells differ by one
+// variable. The first half of the variables index the rows.
+type KarnaughMap struct {
+	RowVariables    []string
+	ColumnVariables []string
+	// RowLabels and ColumnLabels are the values of the row and column
+	// variables, as bit strings in Gray-code order.
+	RowLabels    []string
+	ColumnLabels []string
+	Cells        [][]bool
+	// Groups are the prime implicants of the expression. The ones in the
+	// minimal cover chosen by the minimizer are Selected.
+	Groups []KarnaughGroup
+}
+
+// KarnaughGroup is a rectangle of true cells, wrapping around the edges of
+// the map, matching a product term.
+type KarnaughGroup struct {
+	Term     string
+	Selected bool
+	// Cells are the row and column of every cell of the group.
+	Cells [][2]int
+}
+
+// NewKarnaughMap lays out the expression over its variables, in order of
+// appearance.
+func NewKarnaughMap(n *Node) (*KarnaughMap, error) {
+	variables := n.Variables()
+	if len(variables) > MaxKarnaughVariables {
+		return nil, fmt.Errorf("%w: a Karnaugh map supports up to %d variables, got %d", ErrTooManyVariables, MaxKarnaughVariables, len(variables))
+	}
+
+	count := len(variables)
+	rowCount := count / 2
+	columnCount := count - rowCount
+
+	k := &KarnaughMap{
+		RowVariables:    variables[:rowCount],
+		ColumnVariables: variables[rowCount:],
+		RowLabels:       grayLabels(rowCount),
+		ColumnLabels:    grayLabels(columnCount),
+	}
+
+	// position maps a truth table row to its cell.
+	position := make(map[uint64][2]int, 1<<count)
+
+	var minterms []uint64
+	k.Cells = make([][]bool, len(k.RowLabels))
+	for r := range k.RowLabels {
+		k.Cells[r] = make([]bool, len(k.ColumnLabels))
+		for c := range k.ColumnLabels {
+			row := gray(uint64(r))<<columnCount | gray(uint64(c))
+			position[row] = [2]int{r, c}
+
+			values := make(map[string]bool, count)
+			for v, name := range variables {
+				values[name] = row&(1<<(count-1-v)) != 0
+			}
+
+			k.Cells[r][c] = n.Eval(values)
+			if k.Cells[r][c] {
+				minterms = append(minterms, row)
+			}
+		}
+	}
+
+	// The few variables of a map bound the work, which can neither be
+	// cancelled nor build too many implicants.
+	primes, err := PrimeImplicants(context.Background(), count, minterms, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cover, err := MinimalCover(context.Background(), count, minterms, primes)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[Implicant]bool)
+	for _, term := range cover {
+		selected[term] = true
+	}
+
+	for _, prime := range primes {
+		group := KarnaughGroup{
+			Term:     SumOfProducts(variables, []Implicant{prime}).String(),
+			Selected: selected[prime],
+		}
+		for r := range k.RowLabels {
+			for c := range k.ColumnLabels {
+				row := gray(uint64(r))<<columnCount | gray(uint64(c))
+				if prime.Covers(row) {
+					group.Cells = append(group.Cells, position[row])
+				}
+			}
+		}
+
+		k.Groups = append(k.Groups, group)
+	}
+
+	return k, nil
+}
+
+// gray returns the i-th value of the reflected binary Gray code.
+func gray(i uint64) uint64 {
+	return i ^ i>>1
+}
+
+// grayLabels returns the bit strings of width bits in Gray-code order. Zero
+// bits give a single empty label.
+func grayLabels(width int) []string {
+	labels := make([]string, 1<<width)
+	for i := range labels {
+		if width > 0 {
+			labels[i] = fmt.Sprintf("%0*b", width, gray(uint64(i)))
+		}
+	}
+	return labels
+}
+
+// String renders the map as plain text followed by its groups, the selected
+// ones marked with an asterisk.
+func (k *KarnaughMap) String() string {
+	corner := strings.Join(k.RowVariables, ",") + `\` + strings.Join(k.ColumnVariables, ",")
+
+	rowWidth := len(corner)
+	for _, label := range k.RowLabels {
+		if len(label) > rowWidth {
+			rowWidth = len(label)
+		}
+	}
+
+	columnWidth := 1
+	for _, label := range k.ColumnLabels {
+		if len(label) > columnWidth {
+			columnWidth = len(label)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%*s |", rowWidth, corner)
+	for _, label := range k.ColumnLabels {
+		fmt.Fprintf(&sb, " %*s", columnWidth, label)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("-", rowWidth+1) + "+" + strings.Repeat("-", len(k.ColumnLabels)*(columnWidth+1)) + "\n")
+
+	for r, label := range k.RowLabels {
+		fmt.Fprintf(&sb, "%*s |", rowWidth, label)
+		for _, cell := range k.Cells[r] {
+			value := "0"
+			if cell {
+				value = "1"
+			}
+			fmt.Fprintf(&sb, " %*s", columnWidth, value)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(k.Groups) > 0 {
+		sb.WriteString("\nPrime implicants (* selected):\n")
+		for _, group := range k.Groups {
+			mark := " "
+			if group.Selected {
+				mark = "*"
+			}
+			fmt.Fprintf(&sb, "%s %s\n", mark, group.Term)
+		}
+	}
+
+	return sb.String()
+}
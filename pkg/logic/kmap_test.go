@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKarnaughMap(t *testing.T) {
+	t.Run("returns error when there are too many variables", func(t *testing.T) {
+		node, err := Parse("a AND b AND c AND d AND e AND f AND g")
+		require.NoError(t, err)
+
+		k, err := NewKarnaughMap(node)
+
+		assert.ErrorIs(t, err, ErrTooManyVariables)
+		assert.Nil(t, k)
+	})
+
+	t.Run("lays out the map in Gray-code order", func(t *testing.T) {
+		node, err := Parse("x AND z OR NOT x AND y")
+		require.NoError(t, err)
+
+		k, err := NewKarnaughMap(node)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"x"}, k.RowVariables)
+		assert.Equal(t, []string{"z", "y"}, k.ColumnVariables)
+		assert.Equal(t, []string{"0", "1"}, k.RowLabels)
+		assert.Equal(t, []string{"00", "01", "11", "10"}, k.ColumnLabels)
+		assert.Equal(t, [][]bool{
+			{false, true, true, false},
+			{false, false, true, true},
+		}, k.Cells)
+		assert.Equal(t, []KarnaughGroup{
+			{Term: "NOT x AND y", Selected: true, Cells: [][2]int{{0, 1}, {0, 2}}},
+			{Term: "z AND y", Cells: [][2]int{{0, 2}, {1, 2}}},
+			{Term: "x AND z", Selected: true, Cells: [][2]int{{1, 2}, {1, 3}}},
+		}, k.Groups)
+
+		assert.Equal(t, `x\z,y | 00 01 11 10
+------+------------
+    0 |  0  1  1  0
+    1 |  0  0  1  1
+
+Prime implicants (* selected):
+* NOT x AND y
+  z AND y
+* x AND z
+`, k.String())
+	})
+
+	t.Run("groups wrap around the edges", func(t *testing.T) {
+		node, err := Parse("NOT y")
+		require.NoError(t, err)
+
+		k, err := NewKarnaughMap(node)
+		require.NoError(t, err)
+
+		assert.Equal(t, []KarnaughGroup{
+			{Term: "NOT y", Selected: true, Cells: [][2]int{{0, 0}}},
+		}, k.Groups)
+
+		node, err = Parse("NOT w AND NOT z OR (w AND NOT z)")
+		require.NoError(t, err)
+
+		k, err = NewKarnaughMap(node)
+		require.NoError(t, err)
+
+		assert.Equal(t, []KarnaughGroup{
+			{Term: "NOT z", Selected: true, Cells: [][2]int{{0, 0}, {1, 0}}},
+		}, k.Groups)
+	})
+}
@@ -0,0 +1,334 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+var ErrTooManyImplicants = errors.New("too many prime implicants")
+
+// Implicant is a product term over an ordered list of variables, as used by
+// the Quine-McCluskey minimization. Rows of a truth table over n variables
+// are numbered with the first variable as the most significant bit, so
+// variable i is bit n-1-i. Bits set in Mask are the variables the term
+// doesn't depend on, and Value holds the polarity of the other ones.
+type Implicant struct {
+	Value uint64
+	Mask  uint64
+}
+
+// Covers reports whether the row is one of the assignments of the term.
+func (i Implicant) Covers(row uint64) bool {
+	return row&^i.Mask == i.Value
+}
+
+// Literals returns how many variables the term depends on.
+func (i Implicant) Literals(n int) int {
+	return n - bits.OnesCount64(i.Mask)
+}
+
+// coverSearchBudget bounds the number of branches MinimalCover explores
+// looking for an optimal cover before settling for the best one found.
+const coverSearchBudget = 100000
+
+// MaxPrimeImplicants bounds the number of implicants PrimeImplicants builds
+// at each step, the number of prime implicants growing exponentially with
+// the number of variables in the worst case.
+const MaxPrimeImplicants = 5000
+
+// minimizeCheckInterval is how many implicants or branches the minimization
+// goes through between checks of its context.
+const minimizeCheckInterval = 256
+
+// PrimeImplicants returns the prime implicants of the function over n
+// variables that is true on the minterms, with the don't-cares free to be
+// either value. The result is sorted by number of literals, then by Value
+// and Mask. It fails with ErrTooManyImplicants when a step builds more than
+// MaxPrimeImplicants implicants, and with the context error when it's
+// cancelled.
+func PrimeImplicants(ctx context.Context, n int, minterms, dontCares []uint64) ([]Implicant, error) {
+	current := make(map[Implicant]struct{}, len(minterms)+len(dontCares))
+	for _, row := range minterms {
+		current[Implicant{Value: row}] = struct{}{}
+	}
+	for _, row := range dontCares {
+		current[Implicant{Value: row}] = struct{}{}
+	}
+
+	var primes []Implicant
+	for steps := 0; len(current) > 0; {
+		next := make(map[Implicant]struct{})
+		combined := make(map[Implicant]bool, len(current))
+
+		for implicant := range current {
+			steps++
+			if steps%minimizeCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+
+			for b := 0; b < n; b++ {
+				bit := uint64(1) << b
+				if implicant.Mask&bit != 0 {
+					continue
+				}
+
+				partner := Implicant{Value: implicant.Value ^ bit, Mask: implicant.Mask}
+				if _, ok := current[partner]; !ok {
+					continue
+				}
+
+				combined[implicant] = true
+				next[Implicant{Value: implicant.Value &^ bit, Mask: implicant.Mask | bit}] = struct{}{}
+			}
+
+			if len(next) > MaxPrimeImplicants {
+				return nil, fmt.Errorf("%w: more than %d", ErrTooManyImplicants, MaxPrimeImplicants)
+			}
+		}
+
+		for implicant := range current {
+			if !combined[implicant] {
+				primes = append(primes, implicant)
+			}
+		}
+		if len(primes) > MaxPrimeImplicants {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyImplicants, MaxPrimeImplicants)
+		}
+		current = next
+	}
+
+	sortImplicants(n, primes)
+	return primes, nil
+}
+
+func sortImplicants(n int, implicants []Implicant) {
+	sort.Slice(implicants, func(i, j int) bool {
+		a, b := implicants[i], implicants[j]
+		if a.Literals(n) != b.Literals(n) {
+			return a.Literals(n) < b.Literals(n)
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return a.Mask < b.Mask
+	})
+}
+
+// MinimalCover selects prime implicants covering every minterm, preferring
+// the fewest terms and then the fewest literals. The search is exhaustive
+// for small functions and falls back to the best cover found so far when
+// it grows too large. It fails with the context error when it's cancelled.
+func MinimalCover(ctx context.Context, n int, minterms []uint64, primes []Implicant) ([]Implicant, error) {
+	s := coverSearch{
+		ctx:      ctx,
+		n:        n,
+		primes:   primes,
+		covering: make(map[uint64][]int, len(minterms)),
+		budget:   coverSearchBudget,
+	}
+
+	uncovered := make(map[uint64]struct{}, len(minterms))
+	for _, row := range minterms {
+		uncovered[row] = struct{}{}
+		for p, prime := range primes {
+			if prime.Covers(row) {
+				s.covering[row] = append(s.covering[row], p)
+			}
+		}
+	}
+
+	s.greedy(uncovered)
+	s.search(uncovered, nil)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cover := make([]Implicant, 0, len(s.best))
+	for _, p := range s.best {
+		cover = append(cover, primes[p])
+	}
+
+	sort.Slice(cover, func(i, j int) bool {
+		return termOrder(n, cover[i], cover[j])
+	})
+	return cover, nil
+}
+
+type coverSearch struct {
+	ctx      context.Context
+	n        int
+	primes   []Implicant
+	covering map[uint64][]int
+	budget   int
+
+	best         []int
+	bestLiterals int
+}
+
+func (s *coverSearch) literals(chosen []int) int {
+	total := 0
+	for _, p := range chosen {
+		total += s.primes[p].Literals(s.n)
+	}
+	return total
+}
+
+func (s *coverSearch) record(chosen []int) {
+	literals := s.literals(chosen)
+	if s.best != nil && (len(chosen) > len(s.best) || len(chosen) == len(s.best) && literals >= s.bestLiterals) {
+		return
+	}
+
+	s.best = append([]int(nil), chosen...)
+	s.bestLiterals = literals
+}
+
+// greedy finds a first cover by repeatedly taking the prime implicant that
+// covers the most uncovered minterms, bounding the exhaustive search.
+func (s *coverSearch) greedy(uncovered map[uint64]struct{}) {
+	remaining := make(map[uint64]struct{}, len(uncovered))
+	for row := range uncovered {
+		remaining[row] = struct{}{}
+	}
+
+	var chosen []int
+	for len(remaining) > 0 {
+		bestPrime, bestCount := -1, 0
+		for p, prime := range s.primes {
+			count := 0
+			for row := range remaining {
+				if prime.Covers(row) {
+					count++
+				}
+			}
+			if count > bestCount {
+				bestPrime, bestCount = p, count
+			}
+		}
+
+		chosen = append(chosen, bestPrime)
+		for row := range remaining {
+			if s.primes[bestPrime].Covers(row) {
+				delete(remaining, row)
+			}
+		}
+	}
+
+	s.record(chosen)
+}
+
+func (s *coverSearch) search(uncovered map[uint64]struct{}, chosen []int) {
+	if len(uncovered) == 0 {
+		s.record(chosen)
+		return
+	}
+	if s.budget <= 0 || len(chosen)+1 > len(s.best) {
+		return
+	}
+	s.budget--
+
+	// A cancelled search stops like one out of budget.
+	if s.budget%minimizeCheckInterval == 0 && s.ctx.Err() != nil {
+		s.budget = 0
+		return
+	}
+
+	// Branch on the minterm with the fewest candidates, which makes
+	// essential prime implicants be chosen first.
+	var pivot uint64
+	candidates := -1
+	for row := range uncovered {
+		if count := len(s.covering[row]); candidates < 0 || count < candidates || count == candidates && row < pivot {
+			pivot, candidates = row, count
+		}
+	}
+
+	for _, p := range s.covering[pivot] {
+		remaining := make(map[uint64]struct{}, len(uncovered))
+		for row := range uncovered {
+			if !s.primes[p].Covers(row) {
+				remaining[row] = struct{}{}
+			}
+		}
+
+		s.search(remaining, append(chosen, p))
+	}
+}
+
+// termOrder sorts terms by their literals in variable order, positive
+// literals first and missing variables last.
+func termOrder(n int, a, b Implicant) bool {
+	rank := func(i Implicant, bit uint64) int {
+		switch {
+		case i.Mask&bit != 0:
+			return 2
+		case i.Value&bit != 0:
+			return 0
+		}
+		return 1
+	}
+
+	for v := 0; v < n; v++ {
+		bit := uint64(1) << (n - 1 - v)
+		if ra, rb := rank(a, bit), rank(b, bit); ra != rb {
+			return ra < rb
+		}
+	}
+	return false
+}
+
+// SumOfProducts builds the disjunction of the terms over the variables.
+// No terms is FALSE and a term without literals is TRUE.
+func SumOfProducts(variables []string, terms []Implicant) *Node {
+	n := len(variables)
+	if len(terms) == 0 {
+		return Const(false)
+	}
+
+	products := make([]*Node, 0, len(terms))
+	for _, term := range terms {
+		var literals []*Node
+		for v, name := range variables {
+			bit := uint64(1) << (n - 1 - v)
+			switch {
+			case term.Mask&bit != 0:
+				continue
+			case term.Value&bit != 0:
+				literals = append(literals, Var(name))
+			default:
+				literals = append(literals, Not(Var(name)))
+			}
+		}
+
+		if len(literals) == 0 {
+			return Const(true)
+		}
+		products = append(products, And(literals...))
+	}
+
+	return Or(products...)
+}
+
+// Minimize returns a minimal sum of products over the variables that is
+// true on the minterms and false on every other row except the don't-cares.
+// It fails like PrimeImplicants and MinimalCover.
+func Minimize(ctx context.Context, variables []string, minterms, dontCares []uint64) (*Node, error) {
+	n := len(variables)
+
+	primes, err := PrimeImplicants(ctx, n, minterms, dontCares)
+	if err != nil {
+		return nil, err
+	}
+
+	cover, err := MinimalCover(ctx, n, minterms, primes)
+	if err != nil {
+		return nil, err
+	}
+
+	return SumOfProducts(variables, cover), nil
+}
@@ -0,0 +1,141 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimeImplicants(t *testing.T) {
+	// f(x, y, z) = m(0, 1, 2, 5, 6, 7)
+	primes, err := PrimeImplicants(context.Background(), 3, []uint64{0, 1, 2, 5, 6, 7}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Implicant{
+		{Value: 0b000, Mask: 0b001},
+		{Value: 0b000, Mask: 0b010},
+		{Value: 0b001, Mask: 0b100},
+		{Value: 0b010, Mask: 0b100},
+		{Value: 0b101, Mask: 0b010},
+		{Value: 0b110, Mask: 0b001},
+	}, primes)
+}
+
+func TestMinimize(t *testing.T) {
+	variables := []string{"w", "x", "y", "z"}
+
+	testCases := []struct {
+		name      string
+		variables []string
+		minterms  []uint64
+		dontCares []uint64
+		expect    string
+	}{
+		{
+			name:      "no minterms",
+			variables: variables[:2],
+			expect:    "FALSE",
+		},
+		{
+			name:      "every row",
+			variables: variables[:2],
+			minterms:  []uint64{0, 1, 2, 3},
+			expect:    "TRUE",
+		},
+		{
+			name:      "single variable",
+			variables: variables[:2],
+			minterms:  []uint64{2, 3},
+			expect:    "w",
+		},
+		{
+			name:      "exclusive or",
+			variables: variables[:2],
+			minterms:  []uint64{1, 2},
+			expect:    "w AND NOT x OR NOT w AND x",
+		},
+		{
+			name:      "cyclic cover",
+			variables: variables[:3],
+			minterms:  []uint64{0, 1, 2, 5, 6, 7},
+			expect:    "w AND y OR NOT w AND NOT x OR x AND NOT y",
+		},
+		{
+			name:      "don't-cares",
+			variables: variables,
+			minterms:  []uint64{1, 3, 7, 11, 15},
+			dontCares: []uint64{0, 2, 5},
+			expect:    "NOT w AND NOT x OR y AND z",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Minimize(context.Background(), tc.variables, tc.minterms, tc.dontCares)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, got.String())
+
+			// The result must agree with every row that isn't a don't-care.
+			dontCare := map[uint64]bool{}
+			for _, row := range tc.dontCares {
+				dontCare[row] = true
+			}
+			minterm := map[uint64]bool{}
+			for _, row := range tc.minterms {
+				minterm[row] = true
+			}
+
+			n := len(tc.variables)
+			for row := uint64(0); row < 1<<n; row++ {
+				if dontCare[row] {
+					continue
+				}
+
+				values := map[string]bool{}
+				for v, name := range tc.variables {
+					values[name] = row&(1<<(n-1-v)) != 0
+				}
+				assert.Equal(t, minterm[row], got.Eval(values), "row %d", row)
+			}
+		})
+	}
+}
+
+// adversarialRows returns a function over n variables whose even rows are
+// don't-cares and whose odd multiples of 3 are minterms, which has a huge
+// number of prime implicants.
+func adversarialRows(n int) (minterms, dontCares []uint64) {
+	for row := uint64(0); row < 1<<n; row++ {
+		switch {
+		case row%2 == 0:
+			dontCares = append(dontCares, row)
+		case row%3 == 0:
+			minterms = append(minterms, row)
+		}
+	}
+	return minterms, dontCares
+}
+
+func TestMinimize_Bounds(t *testing.T) {
+	t.Run("fails with too many prime implicants", func(t *testing.T) {
+		minterms, dontCares := adversarialRows(14)
+
+		_, err := PrimeImplicants(context.Background(), 14, minterms, dontCares)
+
+		assert.ErrorIs(t, err, ErrTooManyImplicants)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		minterms, dontCares := adversarialRows(10)
+		variables := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+		_, err := Minimize(ctx, variables, minterms, dontCares)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,260 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownNetlistFormat = errors.New("unknown netlist format")
+
+// NetlistFormat is a hardware description format a tree can be exported to.
+type NetlistFormat string
+
+const (
+	NetlistFormatVerilog NetlistFormat = "verilog"
+	NetlistFormatBLIF    NetlistFormat = "blif"
+)
+
+// NetlistOptions configures the netlist exporters.
+type NetlistOptions struct {
+	// Module names the generated module or model. Defaults to "expression".
+	Module string
+	// Output names the single output. Defaults to "out". Variables can't
+	// contain underscores, so an underscore is appended to it when it
+	// collides with a variable.
+	Output string
+}
+
+func (opts NetlistOptions) withDefaults(n *Node) NetlistOptions {
+	if opts.Module == "" {
+		opts.Module = "expression"
+	}
+	if opts.Output == "" {
+		opts.Output = "out"
+	}
+
+	for _, name := range n.Variables() {
+		if name == opts.Output {
+			opts.Output += "_"
+			break
+		}
+	}
+
+	return opts
+}
+
+// ExportNetlist exports the tree as a combinational circuit with one input
+// per variable, in order of appearance, and a single output.
+func ExportNetlist(n *Node, format NetlistFormat, opts NetlistOptions) (string, error) {
+	switch format {
+	case NetlistFormatVerilog:
+		return ToVerilog(n, opts), nil
+	case NetlistFormatBLIF:
+		return ToBLIF(n, opts), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownNetlistFormat, format)
+}
+
+// verilogKeywords are the reserved words of Verilog made of lowercase
+// letters only, which variables could be named after.
+var verilogKeywords = map[string]struct{}{
+	"always": {}, "and": {}, "assign": {}, "automatic": {}, "begin": {}, "buf": {}, "bufif": {},
+	"case": {}, "casex": {}, "casez": {}, "cell": {}, "cmos": {}, "config": {}, "deassign": {},
+	"default": {}, "defparam": {}, "design": {}, "disable": {}, "edge": {}, "else": {}, "end": {},
+	"endcase": {}, "endconfig": {}, "endfunction": {}, "endgenerate": {}, "endmodule": {},
+	"endprimitive": {}, "endspecify": {}, "endtable": {}, "endtask": {}, "event": {}, "for": {},
+	"force": {}, "forever": {}, "fork": {}, "function": {}, "generate": {}, "genvar": {},
+	"highz": {}, "if": {}, "ifnone": {}, "incdir": {}, "include": {}, "initial": {}, "inout": {},
+	"input": {}, "instance": {}, "integer": {}, "join": {}, "large": {}, "liblist": {},
+	"library": {}, "localparam": {}, "macromodule": {}, "medium": {}, "module": {}, "nand": {},
+	"negedge": {}, "nmos": {}, "nor": {}, "noshowcancelled": {}, "not": {}, "notif": {}, "or": {},
+	"output": {}, "parameter": {}, "pmos": {}, "posedge": {}, "primitive": {}, "pulldown": {},
+	"pullup": {}, "pulsestyleondetect": {}, "pulsestyleonevent": {}, "rcmos": {}, "real": {},
+	"realtime": {}, "reg": {}, "release": {}, "repeat": {}, "rnmos": {}, "rpmos": {}, "rtran": {},
+	"rtranif": {}, "scalared": {}, "showcancelled": {}, "signed": {}, "small": {}, "specify": {},
+	"specparam": {}, "strong": {}, "supply": {}, "table": {}, "task": {}, "time": {}, "tran": {},
+	"tranif": {}, "tri": {}, "triand": {}, "trior": {}, "trireg": {}, "unsigned": {}, "use": {},
+	"uwire": {}, "vectored": {}, "wait": {}, "wand": {}, "weak": {}, "while": {}, "wire": {},
+	"wor": {}, "xnor": {}, "xor": {},
+}
+
+// verilogIdentifier escapes names clashing with a keyword. Escaped
+// identifiers start with a backslash and end with a whitespace.
+func verilogIdentifier(name string) string {
+	if _, ok := verilogKeywords[name]; ok {
+		return `\` + name + " "
+	}
+	return name
+}
+
+// ToVerilog exports the tree as a synthesizable Verilog module driving the
+// output with a continuous assignment.
+func ToVerilog(n *Node, opts NetlistOptions) string {
+	opts = opts.withDefaults(n)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "module %s (\n", verilogIdentifier(opts.Module))
+	for _, name := range n.Variables() {
+		fmt.Fprintf(&sb, "  input wire %s,\n", verilogIdentifier(name))
+	}
+	fmt.Fprintf(&sb, "  output wire %s\n", verilogIdentifier(opts.Output))
+	sb.WriteString(");\n")
+	fmt.Fprintf(&sb, "  assign %s = %s;\n", verilogIdentifier(opts.Output), verilogExpression(n))
+	sb.WriteString("endmodule\n")
+
+	return sb.String()
+}
+
+func verilogExpression(n *Node) string {
+	switch n.Kind {
+	case KindVariable:
+		return verilogIdentifier(n.Name)
+	case KindConstant:
+		if n.Value {
+			return "1'b1"
+		}
+		return "1'b0"
+	case KindNot:
+		return "~" + verilogOperand(n.Operands[0], n)
+	}
+
+	operator := " & "
+	if n.Kind == KindOr {
+		operator = " | "
+	}
+
+	parts := make([]string, 0, len(n.Operands))
+	for _, operand := range n.Operands {
+		parts = append(parts, verilogOperand(operand, n))
+	}
+
+	return strings.Join(parts, operator)
+}
+
+// verilogOperand uses the same precedence rules as Format, which match
+// the ones of the Verilog bitwise operators.
+func verilogOperand(child, parent *Node) string {
+	if precedence(child) >= precedence(parent) {
+		return verilogExpression(child)
+	}
+	return "(" + verilogExpression(child) + ")"
+}
+
+// ToBLIF exports the tree as a BLIF model with one logic gate per operator.
+// Negated variables are folded into the covers of the gates reading them.
+func ToBLIF(n *Node, opts NetlistOptions) string {
+	opts = opts.withDefaults(n)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ".model %s\n", opts.Module)
+	if variables := n.Variables(); len(variables) > 0 {
+		fmt.Fprintf(&sb, ".inputs %s\n", strings.Join(variables, " "))
+	}
+	fmt.Fprintf(&sb, ".outputs %s\n", opts.Output)
+
+	g := &blifGenerator{sb: &sb}
+	g.gate(n, opts.Output)
+
+	sb.WriteString(".end\n")
+
+	return sb.String()
+}
+
+type blifGenerator struct {
+	sb    *strings.Builder
+	wires int
+}
+
+// signal returns the net carrying the value of the node, and whether it
+// must be read negated.
+func (g *blifGenerator) signal(n *Node) (string, bool) {
+	switch {
+	case n.Kind == KindVariable:
+		return n.Name, false
+	case n.Kind == KindNot && n.Operands[0].Kind == KindVariable:
+		return n.Operands[0].Name, true
+	}
+
+	wire := g.wire()
+	g.gate(n, wire)
+
+	return wire, false
+}
+
+// wire returns a new internal net. Variables are made of letters only, so
+// numbered nets can't clash with them.
+func (g *blifGenerator) wire() string {
+	g.wires++
+	return fmt.Sprintf("n%d", g.wires)
+}
+
+func (g *blifGenerator) buffer(input string) string {
+	wire := g.wire()
+	fmt.Fprintf(g.sb, ".names %s %s\n1 1\n", input, wire)
+	return wire
+}
+
+// gate writes the logic gates computing the node into the output net.
+func (g *blifGenerator) gate(n *Node, output string) {
+	switch n.Kind {
+	case KindConstant:
+		fmt.Fprintf(g.sb, ".names %s\n", output)
+		if n.Value {
+			g.sb.WriteString("1\n")
+		}
+		return
+	case KindVariable:
+		fmt.Fprintf(g.sb, ".names %s %s\n1 1\n", n.Name, output)
+		return
+	}
+
+	operands := n.Operands
+	if n.Kind == KindNot {
+		// A negated variable is a single inverted input, and any other
+		// negation reads the net of its operand inverted.
+		operands = []*Node{n}
+		if n.Operands[0].Kind != KindVariable {
+			input, negated := g.signal(n.Operands[0])
+			literal := "0"
+			if negated {
+				literal = "1"
+			}
+			fmt.Fprintf(g.sb, ".names %s %s\n%s 1\n", input, output, literal)
+			return
+		}
+	}
+
+	inputs := make([]string, 0, len(operands))
+	literals := make([]byte, 0, len(operands))
+	seen := make(map[string]struct{}, len(operands))
+	for _, operand := range operands {
+		input, negated := g.signal(operand)
+		if _, ok := seen[input]; ok {
+			// A gate can't read the same net twice, so repeated
+			// variables go through a buffer.
+			input = g.buffer(input)
+		}
+		seen[input] = struct{}{}
+		inputs = append(inputs, input)
+
+		literal := byte('1')
+		if negated {
+			literal = '0'
+		}
+		literals = append(literals, literal)
+	}
+
+	fmt.Fprintf(g.sb, ".names %s %s\n", strings.Join(inputs, " "), output)
+	if n.Kind == KindOr {
+		// One row per input, the other ones being don't-cares.
+		for i, literal := range literals {
+			row := []byte(strings.Repeat("-", len(literals)))
+			row[i] = literal
+			fmt.Fprintf(g.sb, "%s 1\n", row)
+		}
+		return
+	}
+
+	fmt.Fprintf(g.sb, "%s 1\n", literals)
+}
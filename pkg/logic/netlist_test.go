@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportNetlist(t *testing.T) {
+	node, err := Parse("x")
+	require.NoError(t, err)
+
+	netlist, err := ExportNetlist(node, "vhdl", NetlistOptions{})
+
+	assert.ErrorIs(t, err, ErrUnknownNetlistFormat)
+	assert.Empty(t, netlist)
+}
+
+func TestToVerilog(t *testing.T) {
+	testCases := []struct {
+		expression string
+		opts       NetlistOptions
+		expect     string
+	}{
+		{
+			expression: "x AND (y OR NOT z)",
+			expect: `module expression (
+  input wire x,
+  input wire y,
+  input wire z,
+  output wire out
+);
+  assign out = x & (y | ~z);
+endmodule
+`,
+		},
+		{
+			expression: "NOT (out OR wire) AND TRUE",
+			opts:       NetlistOptions{Module: "rule"},
+			expect: `module rule (
+  input wire out,
+  input wire \wire ,
+  output wire out_
+);
+  assign out_ = ~(out | \wire ) & 1'b1;
+endmodule
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			node, err := Parse(tc.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, ToVerilog(node, tc.opts))
+		})
+	}
+}
+
+func TestToBLIF(t *testing.T) {
+	node, err := Parse("x AND (y OR NOT z) OR NOT (x AND y)")
+	require.NoError(t, err)
+
+	assert.Equal(t, `.model expression
+.inputs x y z
+.outputs out
+.names y z n2
+1- 1
+-0 1
+.names x n2 n1
+11 1
+.names x y n4
+11 1
+.names n4 n3
+0 1
+.names n1 n3 out
+1- 1
+-1 1
+.end
+`, ToBLIF(node, NetlistOptions{}))
+}
+
+// TestToBLIF_RoundTrip simulates the exported netlists and checks them
+// against EvaluateLogicalExpression on every assignment.
+func TestToBLIF_RoundTrip(t *testing.T) {
+	expressions := []string{
+		"x",
+		"NOT x",
+		"TRUE",
+		"FALSE",
+		"x AND NOT x",
+		"x OR y OR NOT x",
+		"x AND (y OR NOT z)",
+		"NOT (x AND y) OR (z AND NOT (w OR x))",
+		"(a OR b) AND (c OR NOT d) AND NOT (e AND a)",
+		"NOT (NOT x) AND (out OR NOT y)",
+	}
+
+	for _, expression := range expressions {
+		t.Run(expression, func(t *testing.T) {
+			node, err := Parse(expression)
+			require.NoError(t, err)
+
+			netlist := ToBLIF(node, NetlistOptions{})
+			variables := node.Variables()
+
+			for row := 0; row < 1<<len(variables); row++ {
+				values := make(map[string]bool, len(variables))
+				parameters := make(map[string]int, len(variables))
+				for v, name := range variables {
+					values[name] = row&(1<<v) != 0
+					if values[name] {
+						parameters[name] = 1
+					} else {
+						parameters[name] = 0
+					}
+				}
+
+				expected, err := utils.EvaluateLogicalExpression(expression, parameters)
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, simulateBLIF(t, netlist, values), "parameters %v", parameters)
+			}
+		})
+	}
+}
+
+// blifGate is a .names entry: its output is true when the inputs match
+// any row of the cover.
+type blifGate struct {
+	inputs []string
+	cover  []string
+}
+
+// simulateBLIF evaluates the single output of a combinational BLIF model.
+func simulateBLIF(t *testing.T, netlist string, values map[string]bool) bool {
+	t.Helper()
+
+	var (
+		outputs []string
+		current *blifGate
+	)
+	gates := map[string]*blifGate{}
+
+	scanner := bufio.NewScanner(strings.NewReader(netlist))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case ".model", ".inputs", ".end":
+		case ".outputs":
+			outputs = fields[1:]
+		case ".names":
+			signals := fields[1:]
+			current = &blifGate{inputs: signals[:len(signals)-1]}
+			gates[signals[len(signals)-1]] = current
+		default:
+			require.NotNil(t, current, "cover row outside of a gate")
+			if len(current.inputs) == 0 {
+				require.Equal(t, []string{"1"}, fields)
+				current.cover = append(current.cover, "")
+				continue
+			}
+			require.Equal(t, []string{fields[0], "1"}, fields)
+			current.cover = append(current.cover, fields[0])
+		}
+	}
+	require.Len(t, outputs, 1)
+
+	var eval func(signal string) bool
+	eval = func(signal string) bool {
+		if value, ok := values[signal]; ok {
+			return value
+		}
+
+		gate, ok := gates[signal]
+		require.True(t, ok, "undriven signal %q", signal)
+
+		for _, row := range gate.cover {
+			matches := true
+			for i, input := range gate.inputs {
+				switch row[i] {
+				case '1':
+					matches = matches && eval(input)
+				case '0':
+					matches = matches && !eval(input)
+				}
+			}
+			if matches {
+				return true
+			}
+		}
+		return false
+	}
+
+	return eval(outputs[0])
+}
@@ -0,0 +1,305 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrTooDeep = errors.New("expression too deeply nested")
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTrue
+	tokenFalse
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// SyntaxError describes why a logical expression couldn't be parsed.
+// Pos is the byte offset of the offending token.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// isIdentifier reports whether word is a valid variable name. Variables are
+// made of lowercase letters only, as GetLogicalExpressionParameters expects.
+func isIdentifier(word string) bool {
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return word != ""
+}
+
+func tokenize(logicalExpression string, dialect Dialect) ([]token, error) {
+	spec, ok := dialectSpecs[dialect]
+	if dialect == DialectMixed {
+		var err error
+		if spec, err = mixedSpec(logicalExpression); err != nil {
+			return nil, err
+		}
+	} else if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDialect, dialect)
+	}
+
+	// Longest symbols first, so "&&" isn't read as two "&".
+	symbols := make([]string, 0, len(spec.symbols))
+	for symbol := range spec.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return len(symbols[i]) > len(symbols[j])
+	})
+
+	tokens := []token{}
+	pos := 0
+	for pos < len(logicalExpression) {
+		r, size := utf8.DecodeRuneInString(logicalExpression[pos:])
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			pos += size
+			continue
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			pos += size
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			end := pos
+			for end < len(logicalExpression) {
+				r, size := utf8.DecodeRuneInString(logicalExpression[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				end += size
+			}
+
+			word := logicalExpression[pos:end]
+			if kind, ok := spec.words[word]; ok {
+				tokens = append(tokens, token{kind: kind, text: word, pos: pos})
+			} else if isIdentifier(word) {
+				tokens = append(tokens, token{kind: tokenIdent, text: word, pos: pos})
+			} else {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected word %q", word)}
+			}
+			pos = end
+			continue
+		}
+
+		matched := false
+		for _, symbol := range symbols {
+			if strings.HasPrefix(logicalExpression[pos:], symbol) {
+				tokens = append(tokens, token{kind: spec.symbols[symbol], text: symbol, pos: pos})
+				pos += len(symbol)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(logicalExpression)})
+
+	return tokens, nil
+}
+
+// Parse parses a logical expression written in the canonical dialect.
+func Parse(logicalExpression string) (*Node, error) {
+	return ParseWithDialect(logicalExpression, DialectCanonical)
+}
+
+// ParseWithDialect parses a logical expression written in the given dialect.
+// An empty dialect is detected. NOT binds tighter than AND, which binds
+// tighter than OR.
+func ParseWithDialect(logicalExpression string, dialect Dialect) (*Node, error) {
+	return ParseWithOptions(logicalExpression, ParseOptions{Dialect: dialect})
+}
+
+// ParseOptions configures ParseWithOptions.
+type ParseOptions struct {
+	// Dialect is the dialect of the expression, detected when empty.
+	Dialect Dialect
+	// MaxDepth bounds the nesting of the parentheses and NOT operators, so a
+	// deeply nested text fails with ErrTooDeep before the tree is built.
+	// Redundant parentheses count too. Zero means no limit.
+	MaxDepth int
+}
+
+// ParseWithOptions parses a logical expression like ParseWithDialect.
+func ParseWithOptions(logicalExpression string, opts ParseOptions) (*Node, error) {
+	dialect := opts.Dialect
+	if dialect == "" {
+		var err error
+		if dialect, err = DetectDialect(logicalExpression); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := tokenize(logicalExpression, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, maxDepth: opts.MaxDepth}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+
+	// depth is the nesting of the token being parsed.
+	depth    int
+	maxDepth int
+}
+
+// enter nests the parsing one level deeper, failing beyond maxDepth.
+func (p *parser) enter(tok token) error {
+	p.depth++
+	if p.maxDepth > 0 && p.depth > p.maxDepth {
+		return fmt.Errorf("%w: more than %d levels at position %d", ErrTooDeep, p.maxDepth, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (*Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*Node{left}
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+
+	return Or(operands...), nil
+}
+
+func (p *parser) parseAnd() (*Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*Node{left}
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+
+	return And(operands...), nil
+}
+
+func (p *parser) parseUnary() (*Node, error) {
+	if p.peek().kind == tokenNot {
+		if err := p.enter(p.next()); err != nil {
+			return nil, err
+		}
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+
+		return Not(operand), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (*Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenIdent:
+		return Var(tok.text), nil
+	case tokenTrue:
+		return Const(true), nil
+	case tokenFalse:
+		return Const(false), nil
+	case tokenLParen:
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+
+		closing := p.next()
+		if closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: "unbalanced parenthesis"}
+		}
+
+		return node, nil
+	case tokenEOF:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected end of expression"}
+	}
+
+	return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+}
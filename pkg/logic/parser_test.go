@@ -0,0 +1,148 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expect     *Node
+	}{
+		{
+			expression: "x",
+			expect:     Var("x"),
+		},
+		{
+			expression: "x AND y AND z",
+			expect:     And(Var("x"), Var("y"), Var("z")),
+		},
+		{
+			expression: "x AND y OR z",
+			expect:     Or(And(Var("x"), Var("y")), Var("z")),
+		},
+		{
+			expression: "x AND (y OR z)",
+			expect:     And(Var("x"), Or(Var("y"), Var("z"))),
+		},
+		{
+			expression: "((x OR y) AND (z OR k) OR j)",
+			expect:     Or(And(Or(Var("x"), Var("y")), Or(Var("z"), Var("k"))), Var("j")),
+		},
+		{
+			expression: "NOT x OR NOT (y AND TRUE)",
+			expect:     Or(Not(Var("x")), Not(And(Var("y"), Const(true)))),
+		},
+		{
+			expression: "(x OR y) OR z",
+			expect:     Or(Var("x"), Var("y"), Var("z")),
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := Parse(tc.expression)
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, tc.expect, got, tc.expression)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		expression string
+		err        string
+	}{
+		{
+			expression: "",
+			err:        "syntax error at position 0: unexpected end of expression",
+		},
+		{
+			expression: "x AND",
+			err:        "syntax error at position 5: unexpected end of expression",
+		},
+		{
+			expression: "(x AND y",
+			err:        "syntax error at position 8: unbalanced parenthesis",
+		},
+		{
+			expression: "x y",
+			err:        `syntax error at position 2: unexpected "y"`,
+		},
+		{
+			expression: "x + 1",
+			err:        `syntax error at position 2: unexpected character '+'`,
+		},
+		{
+			expression: "x AND Y",
+			err:        `syntax error at position 6: unexpected word "Y"`,
+		},
+		{
+			expression: "x && y",
+			err:        `syntax error at position 2: unexpected character '&'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := Parse(tc.expression)
+		assert.EqualError(t, err, tc.err, tc.expression)
+		assert.Nil(t, got)
+	}
+}
+
+func TestParseWithDialect(t *testing.T) {
+	expect := Or(And(Var("x"), Var("y")), Not(Var("z")), Const(false))
+
+	testCases := []struct {
+		expression string
+		dialect    Dialect
+	}{
+		{
+			expression: "x AND y OR NOT z OR FALSE",
+			dialect:    DialectCanonical,
+		},
+		{
+			expression: "x && y || !z || false",
+			dialect:    DialectC,
+		},
+		{
+			expression: "x and y or not z or false",
+			dialect:    DialectLowercase,
+		},
+		{
+			expression: "x ∧ y ∨ ¬z ∨ ⊥",
+			dialect:    DialectUnicode,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseWithDialect(tc.expression, tc.dialect)
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, expect, got, tc.expression)
+
+		got, err = ParseWithDialect(tc.expression, "")
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, expect, got, tc.expression)
+	}
+
+	_, err := ParseWithDialect("x AND y", "pascal")
+	assert.ErrorIs(t, err, ErrUnknownDialect)
+}
+
+func TestParseWithOptions_MaxDepth(t *testing.T) {
+	opts := ParseOptions{Dialect: DialectCanonical, MaxDepth: 3}
+
+	_, err := ParseWithOptions("x AND (y OR NOT (z))", opts)
+	require.NoError(t, err)
+
+	_, err = ParseWithOptions("x AND (y OR NOT (NOT z))", opts)
+	assert.ErrorIs(t, err, ErrTooDeep)
+	assert.EqualError(t, err, "expression too deeply nested: more than 3 levels at position 17")
+
+	// The nesting is checked before the tree is built, whatever its depth.
+	deep := strings.Repeat("(", 1000000) + "x" + strings.Repeat(")", 1000000)
+	_, err = ParseWithOptions(deep, opts)
+	assert.ErrorIs(t, err, ErrTooDeep)
+}
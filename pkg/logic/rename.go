@@ -0,0 +1,70 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidVariableName = errors.New("invalid variable name")
+	ErrVariableInUse       = errors.New("variable already in use")
+)
+
+// IsKeyword reports whether the word is a keyword of any dialect. Variables
+// can't be named after one, so expressions stay valid whatever dialect they
+// are written in.
+func IsKeyword(word string) bool {
+	for _, spec := range dialectSpecs {
+		if _, ok := spec.words[word]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RenameVariable rewrites every occurrence of the variable from into to in
+// a logical expression written in the given dialect, keeping the rest of
+// its text as is. It fails when the expression already uses to, since both
+// variables would merge into one.
+func RenameVariable(logicalExpression string, dialect Dialect, from, to string) (string, error) {
+	if dialect == "" {
+		var err error
+		if dialect, err = DetectDialect(logicalExpression); err != nil {
+			return "", err
+		}
+	}
+
+	if !isIdentifier(to) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidVariableName, to)
+	}
+	if IsKeyword(to) {
+		return "", fmt.Errorf("%w: %q is a keyword", ErrInvalidVariableName, to)
+	}
+
+	tokens, err := tokenize(logicalExpression, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, tok := range tokens {
+		if tok.kind != tokenIdent {
+			continue
+		}
+		if tok.text == to && from != to {
+			return "", fmt.Errorf("%w: %q", ErrVariableInUse, to)
+		}
+		if tok.text != from {
+			continue
+		}
+
+		sb.WriteString(logicalExpression[last:tok.pos])
+		sb.WriteString(to)
+		last = tok.pos + len(tok.text)
+	}
+	sb.WriteString(logicalExpression[last:])
+
+	return sb.String(), nil
+}
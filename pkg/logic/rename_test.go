@@ -0,0 +1,112 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameVariable(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		dialect    Dialect
+		from, to   string
+		expect     string
+		err        error
+	}{
+		{
+			name:       "canonical",
+			expression: "(usr AND NOT admin) OR usr",
+			dialect:    DialectCanonical,
+			from:       "usr",
+			to:         "user",
+			expect:     "(user AND NOT admin) OR user",
+		},
+		{
+			name:       "keeps the text of other dialects",
+			expression: "usr&&!(admin ||usr)",
+			dialect:    DialectC,
+			from:       "usr",
+			to:         "user",
+			expect:     "user&&!(admin ||user)",
+		},
+		{
+			name:       "unicode",
+			expression: "¬usr ∧ admin",
+			dialect:    DialectUnicode,
+			from:       "usr",
+			to:         "user",
+			expect:     "¬user ∧ admin",
+		},
+		{
+			name:       "mixed",
+			expression: "usr AND !admin",
+			dialect:    DialectMixed,
+			from:       "usr",
+			to:         "user",
+			expect:     "user AND !admin",
+		},
+		{
+			name:       "only whole names",
+			expression: "x AND xy",
+			dialect:    DialectCanonical,
+			from:       "x",
+			to:         "z",
+			expect:     "z AND xy",
+		},
+		{
+			name:       "unused variable",
+			expression: "x AND y",
+			dialect:    DialectCanonical,
+			from:       "w",
+			to:         "z",
+			expect:     "x AND y",
+		},
+		{
+			name:       "detects the dialect",
+			expression: "x and not y",
+			from:       "y",
+			to:         "z",
+			expect:     "x and not z",
+		},
+		{
+			name:       "variable in use",
+			expression: "x AND y",
+			dialect:    DialectCanonical,
+			from:       "x",
+			to:         "y",
+			err:        ErrVariableInUse,
+		},
+		{
+			name:       "invalid name",
+			expression: "x AND y",
+			dialect:    DialectCanonical,
+			from:       "x",
+			to:         "X",
+			err:        ErrInvalidVariableName,
+		},
+		{
+			name:       "keyword of another dialect",
+			expression: "x AND y",
+			dialect:    DialectCanonical,
+			from:       "x",
+			to:         "not",
+			err:        ErrInvalidVariableName,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenameVariable(tc.expression, tc.dialect, tc.from, tc.to)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var ErrUnmappedVariable = errors.New("variable not mapped to a column")
+
+// SQLFragment is a parameterized WHERE clause fragment. Args hold the values
+// of the placeholders, in order.
+type SQLFragment struct {
+	Where string
+	Args  []interface{}
+}
+
+// SQLOptions configures ToSQL.
+type SQLOptions struct {
+	// Columns maps every variable to the boolean column it reads from.
+	// Columns may be qualified by a table, as in "users.active".
+	Columns map[string]string
+	// ArgsOffset is the number of placeholders already used by the query the
+	// fragment is embedded in, so the first one generated is $(ArgsOffset+1).
+	ArgsOffset int
+}
+
+// ToSQL compiles the tree into a Postgres WHERE fragment. Column names are
+// quoted with pq.QuoteIdentifier and constants are passed as arguments, so
+// nothing from the mapping or the expression is interpolated unquoted.
+func ToSQL(n *Node, opts SQLOptions) (*SQLFragment, error) {
+	unmapped := []string{}
+	for _, name := range n.Variables() {
+		if _, ok := opts.Columns[name]; !ok {
+			unmapped = append(unmapped, name)
+		}
+	}
+	if len(unmapped) > 0 {
+		sort.Strings(unmapped)
+		return nil, fmt.Errorf("%w: %s", ErrUnmappedVariable, strings.Join(unmapped, ", "))
+	}
+
+	g := &sqlGenerator{opts: opts, fragment: &SQLFragment{Args: []interface{}{}}}
+	g.fragment.Where = g.generate(n)
+
+	return g.fragment, nil
+}
+
+type sqlGenerator struct {
+	opts     SQLOptions
+	fragment *SQLFragment
+}
+
+func (g *sqlGenerator) generate(n *Node) string {
+	switch n.Kind {
+	case KindVariable:
+		return quoteColumn(g.opts.Columns[n.Name])
+	case KindConstant:
+		g.fragment.Args = append(g.fragment.Args, n.Value)
+		return fmt.Sprintf("$%d", g.opts.ArgsOffset+len(g.fragment.Args))
+	case KindNot:
+		return "NOT " + g.operand(n.Operands[0], n)
+	}
+
+	parts := make([]string, 0, len(n.Operands))
+	for _, operand := range n.Operands {
+		parts = append(parts, g.operand(operand, n))
+	}
+
+	return strings.Join(parts, " "+n.Kind.String()+" ")
+}
+
+// operand uses the same precedence rules as Format, which match Postgres'.
+func (g *sqlGenerator) operand(child, parent *Node) string {
+	if precedence(child) >= precedence(parent) {
+		return g.generate(child)
+	}
+	return "(" + g.generate(child) + ")"
+}
+
+// quoteColumn quotes each part of a possibly table-qualified column name.
+func quoteColumn(column string) string {
+	parts := strings.Split(column, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
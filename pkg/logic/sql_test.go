@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSQL(t *testing.T) {
+	columns := map[string]string{
+		"x": "is_active",
+		"y": "users.verified",
+		"z": `weird"name`,
+	}
+
+	testCases := []struct {
+		expression string
+		offset     int
+		expect     *SQLFragment
+	}{
+		{
+			expression: "x",
+			expect: &SQLFragment{
+				Where: `"is_active"`,
+				Args:  []interface{}{},
+			},
+		},
+		{
+			expression: "x AND (y OR NOT z)",
+			expect: &SQLFragment{
+				Where: `"is_active" AND ("users"."verified" OR NOT "weird""name")`,
+				Args:  []interface{}{},
+			},
+		},
+		{
+			expression: "(x OR TRUE) AND NOT (y AND FALSE)",
+			offset:     2,
+			expect: &SQLFragment{
+				Where: `("is_active" OR $3) AND NOT ("users"."verified" AND $4)`,
+				Args:  []interface{}{true, false},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		node, err := Parse(tc.expression)
+		require.NoError(t, err)
+
+		got, err := ToSQL(node, SQLOptions{Columns: columns, ArgsOffset: tc.offset})
+		require.NoError(t, err, tc.expression)
+
+		assert.Equal(t, tc.expect, got, tc.expression)
+	}
+}
+
+func TestToSQL_UnmappedVariables(t *testing.T) {
+	node, err := Parse("x AND b OR a")
+	require.NoError(t, err)
+
+	got, err := ToSQL(node, SQLOptions{Columns: map[string]string{"x": "x"}})
+
+	assert.EqualError(t, err, "variable not mapped to a column: a, b")
+	assert.ErrorIs(t, err, ErrUnmappedVariable)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,40 @@
+package logic
+
+// Truth is a value of Kleene's three-valued logic, where Unknown stands for
+// a value that could be either true or false.
+type Truth int8
+
+const (
+	False Truth = iota
+	True
+	Unknown
+)
+
+// TruthOf converts a boolean into a Truth.
+func TruthOf(value bool) Truth {
+	if value {
+		return True
+	}
+	return False
+}
+
+// Not negates the value. The negation of Unknown is Unknown.
+func (t Truth) Not() Truth {
+	switch t {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
+
+func (t Truth) String() string {
+	switch t {
+	case True:
+		return "true"
+	case False:
+		return "false"
+	}
+	return "unknown"
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -15,22 +16,60 @@ var (
 type Expression struct {
 	ID    int64
 	Value string
+	// Original is the expression as it was submitted, when it differs from
+	// the canonical Value, and Dialect is the dialect it was written in.
+	Original string
+	Dialect  string
+	// Namespace groups related expressions, empty for the ones without.
+	Namespace string
+	// CreatedAt and UpdatedAt are in UTC, and Version starts at 1 and is
+	// incremented on every update.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+	// DeletedAt is when the expression was soft deleted, only set by the
+	// operations including the soft deleted expressions.
+	DeletedAt *time.Time
+}
+
+// inUTC sets the timestamps of the expression in UTC, whatever the time
+// zone of the database session.
+func (exp *Expression) inUTC() {
+	exp.CreatedAt = exp.CreatedAt.UTC()
+	exp.UpdatedAt = exp.UpdatedAt.UTC()
+	if exp.DeletedAt != nil {
+		deletedAt := exp.DeletedAt.UTC()
+		exp.DeletedAt = &deletedAt
+	}
 }
 
 type ExpressionRepository interface {
 	GetAllExpressions(ctx context.Context) ([]Expression, error)
 	GetExpressionByID(ctx context.Context, ID int64) (*Expression, error)
-	UpdateExpression(ctx context.Context, exp *Expression) (*Expression, error)
+	UpdateExpression(ctx context.Context, exp *Expression, keepNamespace bool) (*Expression, error)
 	CreateExpression(ctx context.Context, exp *Expression) (*Expression, error)
+	DeleteExpression(ctx context.Context, ID int64) error
+	RestoreExpression(ctx context.Context, ID int64) (*Expression, error)
+	HardDeleteExpression(ctx context.Context, ID int64) error
+	GetExpressionsByVariable(ctx context.Context, name string) ([]Expression, error)
+	RenameVariable(ctx context.Context, from, to string, rewrite func(exp *Expression) error) ([]Expression, error)
 }
 
 func (r *DefaultRepository) GetAllExpressions(ctx context.Context) ([]Expression, error) {
 	const query = `
 		SELECT
 			id,
-			expression
+			expression,
+			original_expression,
+			dialect,
+			namespace,
+			created_at,
+			updated_at,
+			version
 		FROM
 			expressions
+		WHERE
+			deleted_at IS NULL
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -41,16 +80,13 @@ func (r *DefaultRepository) GetAllExpressions(ctx context.Context) ([]Expression
 
 	exps := []Expression{}
 	for rows.Next() {
-		var ID int64
-		var value string
-		if err := rows.Scan(&ID, &value); err != nil {
+		var exp Expression
+		if err := rows.Scan(&exp.ID, &exp.Value, &exp.Original, &exp.Dialect, &exp.Namespace, &exp.CreatedAt, &exp.UpdatedAt, &exp.Version); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
+		exp.inUTC()
 
-		exps = append(exps, Expression{
-			ID:    ID,
-			Value: value,
-		})
+		exps = append(exps, exp)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -63,68 +99,215 @@ func (r *DefaultRepository) GetAllExpressions(ctx context.Context) ([]Expression
 func (r *DefaultRepository) GetExpressionByID(ctx context.Context, ID int64) (*Expression, error) {
 	const query = `
 		SELECT
-			expression
+			expression,
+			original_expression,
+			dialect,
+			namespace,
+			created_at,
+			updated_at,
+			version
 		FROM
 			expressions
 		WHERE
 			id = $1
+			AND deleted_at IS NULL
 	`
 
-	var value string
-	err := r.db.QueryRowContext(ctx, query, ID).Scan(&value)
+	exp := &Expression{ID: ID}
+	err := r.db.QueryRowContext(ctx, query, ID).Scan(&exp.Value, &exp.Original, &exp.Dialect, &exp.Namespace, &exp.CreatedAt, &exp.UpdatedAt, &exp.Version)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("error querying expression ID %d: %w", ID, err)
 	}
 	if err != nil {
 		return nil, ErrExpressionNotFound
 	}
+	exp.inUTC()
 
-	return &Expression{
-		ID:    ID,
-		Value: value,
-	}, nil
+	return exp, nil
 }
 
 func (r *DefaultRepository) CreateExpression(ctx context.Context, exp *Expression) (*Expression, error) {
 	const query = `
 		INSERT INTO expressions
-			(expression)
+			(expression, original_expression, dialect, namespace)
 		VALUES
-			($1)
-		RETURNING id
+			($1, $2, $3, $4)
+		RETURNING
+			id,
+			created_at,
+			updated_at,
+			version
 	`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var ID int64
-	err := r.db.QueryRowContext(ctx, query, exp.Value).Scan(&ID)
+	var createdAt, updatedAt time.Time
+	var version int
+	err = tx.QueryRowContext(ctx, query, exp.Value, exp.Original, exp.Dialect, exp.Namespace).Scan(&ID, &createdAt, &updatedAt, &version)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting new expression: %w", err)
 	}
 
+	if err := indexExpressionVariables(ctx, tx, ID, exp.Value); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	exp.ID = ID
+	exp.CreatedAt, exp.UpdatedAt, exp.Version = createdAt, updatedAt, version
+	exp.inUTC()
 
 	return exp, nil
 }
 
-func (r *DefaultRepository) UpdateExpression(ctx context.Context, exp *Expression) (*Expression, error) {
+// UpdateExpression replaces the expression, moving it to exp.Namespace unless
+// keepNamespace is set, in which case exp.Namespace is set to the stored one.
+func (r *DefaultRepository) UpdateExpression(ctx context.Context, exp *Expression, keepNamespace bool) (*Expression, error) {
 	const query = `
 		UPDATE
 			expressions
 		SET
-			expression = $2
+			expression = $2,
+			original_expression = $3,
+			dialect = $4,
+			namespace = COALESCE($5, namespace),
+			updated_at = now(),
+			version = version + 1
 		WHERE
 			id = $1
+			AND deleted_at IS NULL
+		RETURNING
+			namespace,
+			created_at,
+			updated_at,
+			version
 	`
-	result, err := r.db.ExecContext(ctx, query, exp.ID, exp.Value)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	namespace := sql.NullString{String: exp.Namespace, Valid: !keepNamespace}
+
+	var createdAt, updatedAt time.Time
+	var version int
+	err = tx.QueryRowContext(ctx, query, exp.ID, exp.Value, exp.Original, exp.Dialect, namespace).Scan(&exp.Namespace, &createdAt, &updatedAt, &version)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoRowsAffected
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error updating expression ID %d: %w", exp.ID, err)
 	}
 
+	if err := indexExpressionVariables(ctx, tx, exp.ID, exp.Value); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	exp.CreatedAt, exp.UpdatedAt, exp.Version = createdAt, updatedAt, version
+	exp.inUTC()
+
+	return exp, nil
+}
+
+// DeleteExpression soft deletes the expression: it's kept, along with its
+// indexed variables, but hidden until restored.
+func (r *DefaultRepository) DeleteExpression(ctx context.Context, ID int64) error {
+	const query = `
+		UPDATE
+			expressions
+		SET
+			deleted_at = now()
+		WHERE
+			id = $1
+			AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, ID)
+	if err != nil {
+		return fmt.Errorf("error deleting expression ID %d: %w", ID, err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("error getting number of rows affected: %w", err)
+		return fmt.Errorf("error getting number of rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return nil, ErrNoRowsAffected
+		return ErrExpressionNotFound
+	}
+
+	return nil
+}
+
+// RestoreExpression restores a soft deleted expression, as a new version of
+// it.
+func (r *DefaultRepository) RestoreExpression(ctx context.Context, ID int64) (*Expression, error) {
+	const query = `
+		UPDATE
+			expressions
+		SET
+			deleted_at = NULL,
+			updated_at = now(),
+			version = version + 1
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		RETURNING
+			expression,
+			original_expression,
+			dialect,
+			namespace,
+			created_at,
+			updated_at,
+			version
+	`
+
+	exp := &Expression{ID: ID}
+	err := r.db.QueryRowContext(ctx, query, ID).Scan(&exp.Value, &exp.Original, &exp.Dialect, &exp.Namespace, &exp.CreatedAt, &exp.UpdatedAt, &exp.Version)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error restoring expression ID %d: %w", ID, err)
+	}
+	if err != nil {
+		return nil, ErrExpressionNotFound
 	}
+	exp.inUTC()
 
 	return exp, nil
 }
+
+// HardDeleteExpression deletes the expression for good, whether it's soft
+// deleted or not. Its indexed variables are deleted in cascade.
+func (r *DefaultRepository) HardDeleteExpression(ctx context.Context, ID int64) error {
+	const query = `
+		DELETE FROM
+			expressions
+		WHERE
+			id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, ID)
+	if err != nil {
+		return fmt.Errorf("error hard deleting expression ID %d: %w", ID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting number of rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrExpressionNotFound
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,16 @@ func deleteExpressionsFixture(t *testing.T, ctx context.Context) {
 	require.NoError(t, err)
 }
 
+// withoutTimestamps clears the timestamps of the expressions, checking
+// they're set.
+func withoutTimestamps(t *testing.T, exps ...*Expression) {
+	for _, exp := range exps {
+		assert.False(t, exp.CreatedAt.IsZero())
+		assert.False(t, exp.UpdatedAt.IsZero())
+		exp.CreatedAt, exp.UpdatedAt = time.Time{}, time.Time{}
+	}
+}
+
 func TestDefaultRepository_CreateExpression(t *testing.T) {
 	er := NewRepository(WithDatabaseOption(testConn))
 
@@ -39,6 +50,19 @@ func TestDefaultRepository_CreateExpression(t *testing.T) {
 
 		assert.NotNil(t, exp)
 		assert.NotEmpty(t, exp.ID)
+
+		exp, err = er.CreateExpression(ctx, &Expression{
+			Value:     "x AND NOT z",
+			Original:  "x && !z",
+			Dialect:   "c",
+			Namespace: "billing",
+		})
+		require.NoError(t, err)
+
+		got, err := er.GetExpressionByID(ctx, exp.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, exp, got)
 	})
 }
 
@@ -50,14 +74,14 @@ func TestDefaultRepository_UpdateExpression(t *testing.T) {
 	t.Run("returns error when no rows is affected", func(t *testing.T) {
 		_, err := er.UpdateExpression(ctx, &Expression{
 			Value: "x AND z",
-		})
+		}, false)
 
 		assert.EqualError(t, err, ErrNoRowsAffected.Error())
 
 		_, err = er.UpdateExpression(ctx, &Expression{
 			ID:    999,
 			Value: "x AND z",
-		})
+		}, false)
 
 		assert.EqualError(t, err, ErrNoRowsAffected.Error())
 	})
@@ -68,10 +92,33 @@ func TestDefaultRepository_UpdateExpression(t *testing.T) {
 		exp, err := er.UpdateExpression(ctx, &Expression{
 			ID:    ID,
 			Value: "(x AND z OR y)",
-		})
+		}, false)
 		require.NoError(t, err)
 
 		assert.NotEqual(t, exp.Value, "x AND z")
+		assert.Equal(t, 2, exp.Version)
+		assert.False(t, exp.UpdatedAt.Before(exp.CreatedAt))
+
+		got, err := er.GetExpressionByID(ctx, ID)
+		require.NoError(t, err)
+		assert.Equal(t, exp, got)
+	})
+
+	t.Run("moves the expression or keeps its namespace", func(t *testing.T) {
+		created, err := er.CreateExpression(ctx, &Expression{Value: "x", Namespace: "billing"})
+		require.NoError(t, err)
+
+		exp, err := er.UpdateExpression(ctx, &Expression{ID: created.ID, Value: "x OR y"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, "billing", exp.Namespace)
+
+		exp, err = er.UpdateExpression(ctx, &Expression{ID: created.ID, Value: "x OR y"}, false)
+		require.NoError(t, err)
+		assert.Empty(t, exp.Namespace)
+
+		got, err := er.GetExpressionByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.Namespace)
 	})
 }
 
@@ -94,21 +141,27 @@ func TestDefaultRepository_GetAllExpressions(t *testing.T) {
 
 		expectedExps := []Expression{
 			{
-				ID:    expID1,
-				Value: "x AND y",
+				ID:      expID1,
+				Value:   "x AND y",
+				Version: 1,
 			},
 			{
-				ID:    expID2,
-				Value: "x OR y",
+				ID:      expID2,
+				Value:   "x OR y",
+				Version: 1,
 			},
 			{
-				ID:    expID3,
-				Value: "a AND b",
+				ID:      expID3,
+				Value:   "a AND b",
+				Version: 1,
 			},
 		}
 
 		exps, err = er.GetAllExpressions(ctx)
 		require.NoError(t, err)
+		for i := range exps {
+			withoutTimestamps(t, &exps[i])
+		}
 
 		assert.Equal(t, expectedExps, exps)
 	})
@@ -132,13 +185,94 @@ func TestDefaultRepository_GetExpressionByID(t *testing.T) {
 		expID := makeExpressionFixture(t, ctx, "x AND y")
 
 		expectedExp := &Expression{
-			ID:    expID,
-			Value: "x AND y",
+			ID:      expID,
+			Value:   "x AND y",
+			Version: 1,
 		}
 
 		exp, err := er.GetExpressionByID(ctx, expID)
 		require.NoError(t, err)
+		withoutTimestamps(t, exp)
 
 		assert.Equal(t, expectedExp, exp)
 	})
 }
+
+func TestDefaultRepository_DeleteExpression(t *testing.T) {
+	er := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("returns error when expression is not found", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		err := er.DeleteExpression(ctx, 1)
+		assert.Equal(t, ErrExpressionNotFound, err)
+
+		_, err = er.RestoreExpression(ctx, 1)
+		assert.Equal(t, ErrExpressionNotFound, err)
+
+		err = er.HardDeleteExpression(ctx, 1)
+		assert.Equal(t, ErrExpressionNotFound, err)
+	})
+
+	t.Run("soft deletes and restores expressions", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		expID := makeExpressionFixture(t, ctx, "x AND y")
+
+		err := er.DeleteExpression(ctx, expID)
+		require.NoError(t, err)
+
+		_, err = er.GetExpressionByID(ctx, expID)
+		assert.Equal(t, ErrExpressionNotFound, err)
+
+		exps, err := er.GetAllExpressions(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, exps)
+
+		exps, err = er.GetExpressionsByVariable(ctx, "x")
+		require.NoError(t, err)
+		assert.Empty(t, exps)
+
+		_, err = er.UpdateExpression(ctx, &Expression{ID: expID, Value: "x"}, false)
+		assert.Equal(t, ErrNoRowsAffected, err)
+
+		err = er.DeleteExpression(ctx, expID)
+		assert.Equal(t, ErrExpressionNotFound, err)
+
+		exp, err := er.RestoreExpression(ctx, expID)
+		require.NoError(t, err)
+
+		got, err := er.GetExpressionByID(ctx, expID)
+		require.NoError(t, err)
+		assert.Equal(t, exp, got)
+
+		withoutTimestamps(t, exp)
+		assert.Equal(t, &Expression{ID: expID, Value: "x AND y", Version: 2}, exp)
+
+		_, err = er.RestoreExpression(ctx, expID)
+		assert.Equal(t, ErrExpressionNotFound, err)
+	})
+
+	t.Run("hard deletes expressions", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		exp, err := er.CreateExpression(ctx, &Expression{Value: "x AND y"})
+		require.NoError(t, err)
+
+		err = er.DeleteExpression(ctx, exp.ID)
+		require.NoError(t, err)
+
+		err = er.HardDeleteExpression(ctx, exp.ID)
+		require.NoError(t, err)
+
+		_, err = er.RestoreExpression(ctx, exp.ID)
+		assert.Equal(t, ErrExpressionNotFound, err)
+
+		var indexed int
+		err = testConn.QueryRowContext(ctx, `SELECT count(*) FROM expression_variables WHERE expression_id = $1`, exp.ID).Scan(&indexed)
+		require.NoError(t, err)
+		assert.Zero(t, indexed)
+	})
+}
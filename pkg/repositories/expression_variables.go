@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+	"github.com/lib/pq"
+)
+
+// indexExpressionVariables replaces the indexed variables of the expression
+// with the ones of its value.
+func indexExpressionVariables(ctx context.Context, tx *sql.Tx, ID int64, value string) error {
+	variables := []string{}
+	for name := range utils.GetLogicalExpressionParameters(value) {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+
+	const deleteQuery = `
+		DELETE FROM
+			expression_variables
+		WHERE
+			expression_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, deleteQuery, ID); err != nil {
+		return fmt.Errorf("error unindexing the variables of expression ID %d: %w", ID, err)
+	}
+
+	const insertQuery = `
+		INSERT INTO expression_variables
+			(expression_id, variable)
+		SELECT
+			$1, unnest($2::text[])
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, ID, pq.StringArray(variables)); err != nil {
+		return fmt.Errorf("error indexing the variables of expression ID %d: %w", ID, err)
+	}
+
+	return nil
+}
+
+// expressionsByVariableQuery selects the expressions using a variable, the
+// soft deleted ones included when its second parameter is true.
+const expressionsByVariableQuery = `
+	SELECT
+		e.id,
+		e.expression,
+		e.original_expression,
+		e.dialect,
+		e.namespace,
+		e.created_at,
+		e.updated_at,
+		e.version,
+		e.deleted_at
+	FROM
+		expressions e
+		JOIN expression_variables v ON v.expression_id = e.id
+	WHERE
+		v.variable = $1
+		AND (e.deleted_at IS NULL OR $2)
+	ORDER BY
+		e.id
+`
+
+func scanExpressions(rows *sql.Rows) ([]Expression, error) {
+	defer rows.Close()
+
+	exps := []Expression{}
+	for rows.Next() {
+		var exp Expression
+		if err := rows.Scan(&exp.ID, &exp.Value, &exp.Original, &exp.Dialect, &exp.Namespace, &exp.CreatedAt, &exp.UpdatedAt, &exp.Version, &exp.DeletedAt); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		exp.inUTC()
+
+		exps = append(exps, exp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning rows: %w", err)
+	}
+
+	return exps, nil
+}
+
+// GetExpressionsByVariable returns the expressions using the variable,
+// sorted by ID.
+func (r *DefaultRepository) GetExpressionsByVariable(ctx context.Context, name string) ([]Expression, error) {
+	rows, err := r.db.QueryContext(ctx, expressionsByVariableQuery, name, false)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the expressions using variable %q: %w", name, err)
+	}
+
+	return scanExpressions(rows)
+}
+
+// RenameVariable renames the variable in every expression using it, soft
+// deleted or not, and in the variables registry, in a single transaction
+// rolled back as a whole when rewrite, which renames the variable in the text
+// of each expression, fails. The rewritten expressions are returned sorted by
+// ID, with the DeletedAt of the soft deleted ones set.
+func (r *DefaultRepository) RenameVariable(ctx context.Context, from, to string, rewrite func(exp *Expression) error) ([]Expression, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, expressionsByVariableQuery+` FOR UPDATE OF e`, from, true)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the expressions using variable %q: %w", from, err)
+	}
+
+	exps, err := scanExpressions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	const updateQuery = `
+		UPDATE
+			expressions
+		SET
+			expression = $2,
+			original_expression = $3,
+			dialect = $4,
+			updated_at = now(),
+			version = version + 1
+		WHERE
+			id = $1
+		RETURNING
+			updated_at,
+			version
+	`
+	for i := range exps {
+		if err := rewrite(&exps[i]); err != nil {
+			return nil, fmt.Errorf("error renaming variable %q in expression ID %d: %w", from, exps[i].ID, err)
+		}
+
+		err := tx.QueryRowContext(ctx, updateQuery, exps[i].ID, exps[i].Value, exps[i].Original, exps[i].Dialect).Scan(&exps[i].UpdatedAt, &exps[i].Version)
+		if err != nil {
+			return nil, fmt.Errorf("error updating expression ID %d: %w", exps[i].ID, err)
+		}
+		exps[i].inUTC()
+
+		if err := indexExpressionVariables(ctx, tx, exps[i].ID, exps[i].Value); err != nil {
+			return nil, err
+		}
+	}
+
+	const renameQuery = `
+		UPDATE
+			variables
+		SET
+			name = $2
+		WHERE
+			name = $1
+	`
+	_, err = tx.ExecContext(ctx, renameQuery, from, to)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, ErrVariableAlreadyExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error renaming registered variable %q: %w", from, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return exps, nil
+}
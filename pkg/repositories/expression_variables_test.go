@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRepository_GetExpressionsByVariable(t *testing.T) {
+	er := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("returns the expressions using the variable", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		exp1, err := er.CreateExpression(ctx, &Expression{Value: "x AND y"})
+		require.NoError(t, err)
+		exp2, err := er.CreateExpression(ctx, &Expression{Value: "y OR z"})
+		require.NoError(t, err)
+		_, err = er.CreateExpression(ctx, &Expression{Value: "z"})
+		require.NoError(t, err)
+
+		exps, err := er.GetExpressionsByVariable(ctx, "y")
+		require.NoError(t, err)
+
+		assert.Equal(t, []Expression{*exp1, *exp2}, exps)
+
+		_, err = er.UpdateExpression(ctx, &Expression{ID: exp1.ID, Value: "x AND w"}, false)
+		require.NoError(t, err)
+
+		exps, err = er.GetExpressionsByVariable(ctx, "y")
+		require.NoError(t, err)
+
+		assert.Equal(t, []Expression{*exp2}, exps)
+
+		exps, err = er.GetExpressionsByVariable(ctx, "v")
+		require.NoError(t, err)
+
+		assert.Empty(t, exps)
+	})
+}
+
+func TestDefaultRepository_RenameVariable(t *testing.T) {
+	er := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	rename := func(exp *Expression) error {
+		exp.Value = strings.ReplaceAll(exp.Value, "y", "w")
+		return nil
+	}
+
+	t.Run("renames the variable in every expression", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+		deleteVariablesFixture(t, ctx)
+
+		exp1, err := er.CreateExpression(ctx, &Expression{Value: "x AND y"})
+		require.NoError(t, err)
+		_, err = er.CreateExpression(ctx, &Expression{Value: "z"})
+		require.NoError(t, err)
+		_, err = er.CreateVariable(ctx, &Variable{Name: "y", Type: "boolean"})
+		require.NoError(t, err)
+
+		exps, err := er.RenameVariable(ctx, "y", "w", rename)
+		require.NoError(t, err)
+
+		got, err := er.GetExpressionByID(ctx, exp1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []Expression{*got}, exps)
+
+		withoutTimestamps(t, got)
+		assert.Equal(t, &Expression{ID: exp1.ID, Value: "x AND w", Version: 2}, got)
+
+		exps, err = er.GetExpressionsByVariable(ctx, "y")
+		require.NoError(t, err)
+		assert.Empty(t, exps)
+
+		exps, err = er.GetExpressionsByVariable(ctx, "w")
+		require.NoError(t, err)
+		assert.Len(t, exps, 1)
+
+		_, err = er.GetVariableByName(ctx, "w")
+		require.NoError(t, err)
+	})
+
+	t.Run("renames the variable in the soft deleted expressions", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		exp1, err := er.CreateExpression(ctx, &Expression{Value: "x AND y"})
+		require.NoError(t, err)
+		exp2, err := er.CreateExpression(ctx, &Expression{Value: "y OR z"})
+		require.NoError(t, err)
+		require.NoError(t, er.DeleteExpression(ctx, exp2.ID))
+
+		exps, err := er.RenameVariable(ctx, "y", "w", rename)
+		require.NoError(t, err)
+		require.Len(t, exps, 2)
+
+		assert.Equal(t, exp1.ID, exps[0].ID)
+		assert.Nil(t, exps[0].DeletedAt)
+		assert.Equal(t, exp2.ID, exps[1].ID)
+		assert.Equal(t, "w OR z", exps[1].Value)
+		assert.NotNil(t, exps[1].DeletedAt)
+
+		_, err = er.GetExpressionByID(ctx, exp2.ID)
+		assert.Equal(t, ErrExpressionNotFound, err)
+	})
+
+	t.Run("stores the dialect set by the rewrite", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		// A legacy expression, upgraded by the rewrite to its dialect.
+		created, err := er.CreateExpression(ctx, &Expression{Value: "x && y"})
+		require.NoError(t, err)
+
+		exps, err := er.RenameVariable(ctx, "y", "w", func(exp *Expression) error {
+			exp.Value, exp.Original, exp.Dialect = "x AND w", "x && w", "c"
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, exps, 1)
+
+		got, err := er.GetExpressionByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "x AND w", got.Value)
+		assert.Equal(t, "x && w", got.Original)
+		assert.Equal(t, "c", got.Dialect)
+	})
+
+	t.Run("rolls back when an expression can't be rewritten", func(t *testing.T) {
+		deleteExpressionsFixture(t, ctx)
+
+		exp1, err := er.CreateExpression(ctx, &Expression{Value: "x AND y"})
+		require.NoError(t, err)
+		_, err = er.CreateExpression(ctx, &Expression{Value: "y OR w"})
+		require.NoError(t, err)
+
+		_, err = er.RenameVariable(ctx, "y", "w", func(exp *Expression) error {
+			if strings.Contains(exp.Value, "w") {
+				return errors.New("variable in use")
+			}
+			return rename(exp)
+		})
+		assert.ErrorContains(t, err, "variable in use")
+
+		got, err := er.GetExpressionByID(ctx, exp1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "x AND y", got.Value)
+	})
+}
@@ -27,3 +27,4 @@ func WithDatabaseOption(db *sql.DB) DefaultRepositoryOption {
 }
 
 var _ ExpressionRepository = (*DefaultRepository)(nil)
+var _ VariableRepository = (*DefaultRepository)(nil)
@@ -34,12 +34,106 @@ func (er *ExpressionRepositoryMock) GetExpressionByID(ctx context.Context, ID in
 	return args.Get(0).(*Expression), args.Error(1)
 }
 
-func (er *ExpressionRepositoryMock) UpdateExpression(ctx context.Context, exp *Expression) (*Expression, error) {
-	args := er.Called(ctx, exp)
+func (er *ExpressionRepositoryMock) UpdateExpression(ctx context.Context, exp *Expression, keepNamespace bool) (*Expression, error) {
+	args := er.Called(ctx, exp, keepNamespace)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*Expression), args.Error(1)
 }
 
+func (er *ExpressionRepositoryMock) DeleteExpression(ctx context.Context, ID int64) error {
+	args := er.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (er *ExpressionRepositoryMock) RestoreExpression(ctx context.Context, ID int64) (*Expression, error) {
+	args := er.Called(ctx, ID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Expression), args.Error(1)
+}
+
+func (er *ExpressionRepositoryMock) HardDeleteExpression(ctx context.Context, ID int64) error {
+	args := er.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (er *ExpressionRepositoryMock) GetExpressionsByVariable(ctx context.Context, name string) ([]Expression, error) {
+	args := er.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Expression), args.Error(1)
+}
+
+// RenameVariable applies rewrite to the expressions returned by the mock,
+// as the repository does before storing them.
+func (er *ExpressionRepositoryMock) RenameVariable(ctx context.Context, from, to string, rewrite func(exp *Expression) error) ([]Expression, error) {
+	args := er.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	exps := append([]Expression(nil), args.Get(0).([]Expression)...)
+	for i := range exps {
+		if err := rewrite(&exps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return exps, args.Error(1)
+}
+
 var _ ExpressionRepository = (*ExpressionRepositoryMock)(nil)
+
+type VariableRepositoryMock struct {
+	mock.Mock
+}
+
+func (vr *VariableRepositoryMock) GetAllVariables(ctx context.Context) ([]Variable, error) {
+	args := vr.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Variable), args.Error(1)
+}
+
+func (vr *VariableRepositoryMock) GetVariablesByName(ctx context.Context, names []string) ([]Variable, error) {
+	args := vr.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Variable), args.Error(1)
+}
+
+func (vr *VariableRepositoryMock) GetVariableByName(ctx context.Context, name string) (*Variable, error) {
+	args := vr.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Variable), args.Error(1)
+}
+
+func (vr *VariableRepositoryMock) CreateVariable(ctx context.Context, v *Variable) (*Variable, error) {
+	args := vr.Called(ctx, v)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Variable), args.Error(1)
+}
+
+func (vr *VariableRepositoryMock) UpdateVariable(ctx context.Context, v *Variable) (*Variable, error) {
+	args := vr.Called(ctx, v)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Variable), args.Error(1)
+}
+
+func (vr *VariableRepositoryMock) DeleteVariable(ctx context.Context, name string) error {
+	args := vr.Called(ctx, name)
+	return args.Error(0)
+}
+
+var _ VariableRepository = (*VariableRepositoryMock)(nil)
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrVariableNotFound      = errors.New("variable not found")
+	ErrVariableAlreadyExists = errors.New("variable already exists")
+)
+
+// uniqueViolation is the Postgres error code of unique constraint violations.
+const uniqueViolation = "23505"
+
+// Variable is the schema of a variable used by the expressions.
+type Variable struct {
+	Name string
+	// Type is one of "boolean", "integer" or "enum".
+	Type string
+	// Min and Max bound the values of integer variables, when set.
+	Min *int
+	Max *int
+	// Enum lists the values allowed for enum variables.
+	Enum []int
+	// Default is the value of the variable when it's absent from an
+	// evaluation defaulting absent variables, if any.
+	Default     *int
+	Description string
+}
+
+type VariableRepository interface {
+	GetAllVariables(ctx context.Context) ([]Variable, error)
+	GetVariablesByName(ctx context.Context, names []string) ([]Variable, error)
+	GetVariableByName(ctx context.Context, name string) (*Variable, error)
+	CreateVariable(ctx context.Context, v *Variable) (*Variable, error)
+	UpdateVariable(ctx context.Context, v *Variable) (*Variable, error)
+	DeleteVariable(ctx context.Context, name string) error
+}
+
+const variableColumns = `
+	name,
+	type,
+	min_value,
+	max_value,
+	allowed_values,
+	default_value,
+	description
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVariable(row rowScanner) (*Variable, error) {
+	var (
+		v                            Variable
+		minValue, maxValue, defValue sql.NullInt64
+		enum                         pq.Int64Array
+	)
+	if err := row.Scan(&v.Name, &v.Type, &minValue, &maxValue, &enum, &defValue, &v.Description); err != nil {
+		return nil, err
+	}
+
+	v.Min, v.Max, v.Default = nullInt(minValue), nullInt(maxValue), nullInt(defValue)
+	for _, value := range enum {
+		v.Enum = append(v.Enum, int(value))
+	}
+
+	return &v, nil
+}
+
+func nullInt(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	value := int(n.Int64)
+	return &value
+}
+
+func nullableInt(value *int) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
+
+func int64Array(values []int) pq.Int64Array {
+	array := make(pq.Int64Array, 0, len(values))
+	for _, value := range values {
+		array = append(array, int64(value))
+	}
+	return array
+}
+
+func (r *DefaultRepository) queryVariables(ctx context.Context, query string, args ...interface{}) ([]Variable, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying variables: %w", err)
+	}
+	defer rows.Close()
+
+	vars := []Variable{}
+	for rows.Next() {
+		v, err := scanVariable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		vars = append(vars, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning rows: %w", err)
+	}
+
+	return vars, nil
+}
+
+func (r *DefaultRepository) GetAllVariables(ctx context.Context) ([]Variable, error) {
+	query := `SELECT ` + variableColumns + ` FROM variables ORDER BY name`
+
+	return r.queryVariables(ctx, query)
+}
+
+// GetVariablesByName returns the registered variables among the names.
+// Unregistered names are skipped.
+func (r *DefaultRepository) GetVariablesByName(ctx context.Context, names []string) ([]Variable, error) {
+	query := `SELECT ` + variableColumns + ` FROM variables WHERE name = ANY($1) ORDER BY name`
+
+	return r.queryVariables(ctx, query, pq.StringArray(names))
+}
+
+func (r *DefaultRepository) GetVariableByName(ctx context.Context, name string) (*Variable, error) {
+	query := `SELECT ` + variableColumns + ` FROM variables WHERE name = $1`
+
+	v, err := scanVariable(r.db.QueryRowContext(ctx, query, name))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error querying variable %q: %w", name, err)
+	}
+	if err != nil {
+		return nil, ErrVariableNotFound
+	}
+
+	return v, nil
+}
+
+func (r *DefaultRepository) CreateVariable(ctx context.Context, v *Variable) (*Variable, error) {
+	const query = `
+		INSERT INTO variables
+			(name, type, min_value, max_value, allowed_values, default_value, description)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(ctx, query, v.Name, v.Type, nullableInt(v.Min), nullableInt(v.Max), int64Array(v.Enum), nullableInt(v.Default), v.Description)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, ErrVariableAlreadyExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error inserting new variable: %w", err)
+	}
+
+	return v, nil
+}
+
+func (r *DefaultRepository) UpdateVariable(ctx context.Context, v *Variable) (*Variable, error) {
+	const query = `
+		UPDATE
+			variables
+		SET
+			type = $2,
+			min_value = $3,
+			max_value = $4,
+			allowed_values = $5,
+			default_value = $6,
+			description = $7
+		WHERE
+			name = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, v.Name, v.Type, nullableInt(v.Min), nullableInt(v.Max), int64Array(v.Enum), nullableInt(v.Default), v.Description)
+	if err != nil {
+		return nil, fmt.Errorf("error updating variable %q: %w", v.Name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting number of rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrVariableNotFound
+	}
+
+	return v, nil
+}
+
+func (r *DefaultRepository) DeleteVariable(ctx context.Context, name string) error {
+	const query = `
+		DELETE FROM
+			variables
+		WHERE
+			name = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, name)
+	if err != nil {
+		return fmt.Errorf("error deleting variable %q: %w", name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting number of rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrVariableNotFound
+	}
+
+	return nil
+}
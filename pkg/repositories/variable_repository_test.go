@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deleteVariablesFixture(t *testing.T, ctx context.Context) {
+	query := `
+		DELETE FROM variables
+	`
+	_, err := testConn.ExecContext(ctx, query)
+	require.NoError(t, err)
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestDefaultRepository_CreateVariable(t *testing.T) {
+	vr := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("inserts variables successfully", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		v, err := vr.CreateVariable(ctx, &Variable{
+			Name:        "age",
+			Type:        "integer",
+			Min:         intPointer(0),
+			Max:         intPointer(130),
+			Description: "Age of the customer",
+		})
+		require.NoError(t, err)
+
+		got, err := vr.GetVariableByName(ctx, "age")
+		require.NoError(t, err)
+
+		assert.Equal(t, v, got)
+
+		v, err = vr.CreateVariable(ctx, &Variable{
+			Name:    "tier",
+			Type:    "enum",
+			Enum:    []int{1, 2, 3},
+			Default: intPointer(1),
+		})
+		require.NoError(t, err)
+
+		got, err = vr.GetVariableByName(ctx, "tier")
+		require.NoError(t, err)
+
+		assert.Equal(t, v, got)
+	})
+
+	t.Run("returns error when variable already exists", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		_, err := vr.CreateVariable(ctx, &Variable{Name: "x", Type: "boolean"})
+		require.NoError(t, err)
+
+		_, err = vr.CreateVariable(ctx, &Variable{Name: "x", Type: "boolean"})
+		assert.ErrorIs(t, err, ErrVariableAlreadyExists)
+	})
+}
+
+func TestDefaultRepository_UpdateVariable(t *testing.T) {
+	vr := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("returns error when variable is not found", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		_, err := vr.UpdateVariable(ctx, &Variable{Name: "x", Type: "boolean"})
+		assert.EqualError(t, err, ErrVariableNotFound.Error())
+	})
+
+	t.Run("updates variables successfully", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		_, err := vr.CreateVariable(ctx, &Variable{Name: "x", Type: "boolean"})
+		require.NoError(t, err)
+
+		v, err := vr.UpdateVariable(ctx, &Variable{Name: "x", Type: "integer", Max: intPointer(10)})
+		require.NoError(t, err)
+
+		got, err := vr.GetVariableByName(ctx, "x")
+		require.NoError(t, err)
+
+		assert.Equal(t, v, got)
+	})
+}
+
+func TestDefaultRepository_DeleteVariable(t *testing.T) {
+	vr := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("returns error when variable is not found", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		err := vr.DeleteVariable(ctx, "x")
+		assert.EqualError(t, err, ErrVariableNotFound.Error())
+	})
+
+	t.Run("deletes variables successfully", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		_, err := vr.CreateVariable(ctx, &Variable{Name: "x", Type: "boolean"})
+		require.NoError(t, err)
+
+		err = vr.DeleteVariable(ctx, "x")
+		require.NoError(t, err)
+
+		_, err = vr.GetVariableByName(ctx, "x")
+		assert.EqualError(t, err, ErrVariableNotFound.Error())
+	})
+}
+
+func TestDefaultRepository_GetVariables(t *testing.T) {
+	vr := NewRepository(WithDatabaseOption(testConn))
+
+	ctx := context.Background()
+
+	t.Run("returns variables sorted by name", func(t *testing.T) {
+		deleteVariablesFixture(t, ctx)
+
+		vars, err := vr.GetAllVariables(ctx)
+		require.NoError(t, err)
+
+		assert.Empty(t, vars)
+
+		for _, name := range []string{"y", "x", "z"} {
+			_, err := vr.CreateVariable(ctx, &Variable{Name: name, Type: "boolean"})
+			require.NoError(t, err)
+		}
+
+		vars, err = vr.GetAllVariables(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Variable{
+			{Name: "x", Type: "boolean"},
+			{Name: "y", Type: "boolean"},
+			{Name: "z", Type: "boolean"},
+		}, vars)
+
+		vars, err = vr.GetVariablesByName(ctx, []string{"z", "w", "x"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []Variable{
+			{Name: "x", Type: "boolean"},
+			{Name: "z", Type: "boolean"},
+		}, vars)
+	})
+}
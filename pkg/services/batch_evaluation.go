@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+)
+
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// BatchEvaluationOptions configures EvaluateBatch.
+type BatchEvaluationOptions struct {
+	EvaluationOptions
+	// Workers bounds the number of parameter sets evaluated concurrently.
+	// Defaults to GOMAXPROCS.
+	Workers int
+}
+
+// BatchResult is the outcome of the evaluation of one parameter set.
+type BatchResult struct {
+	Result logic.Truth
+	// Err is why the parameter set couldn't be evaluated, the other ones
+	// being evaluated regardless.
+	Err error
+}
+
+// EvaluateBatch evaluates the expression against every parameter set, as
+// EvaluateExpressionWithOptions does, loading and compiling it once. The
+// results are in the order of the parameter sets. The evaluation stops when
+// the context is done, failing the whole batch.
+func (es *expressionService) EvaluateBatch(ctx context.Context, ID int64, parameters []map[string]int, opts BatchEvaluationOptions) ([]BatchResult, error) {
+	if es.limits.MaxBatchSize > 0 && len(parameters) > es.limits.MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d parameter sets, the limit is %d", ErrBatchTooLarge, len(parameters), es.limits.MaxBatchSize)
+	}
+
+	e, err := es.prepareEvaluation(ctx, ID, opts.EvaluationOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Boolean expressions are evaluated logic.BitLanes parameter sets at a
+	// time, and the other ones one by one.
+	step := 1
+	var program *logic.BitProgram
+	if e.bitParallel() {
+		program = logic.CompileBits(e.node)
+		step = logic.BitLanes
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunks := (len(parameters) + step - 1) / step; workers > chunks {
+		workers = chunks
+	}
+
+	results := make([]BatchResult, len(parameters))
+	starts := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for start := range starts {
+				if program == nil {
+					results[start].Result, results[start].Err = e.evaluate(ctx, parameters[start])
+					continue
+				}
+
+				end := start + step
+				if end > len(parameters) {
+					end = len(parameters)
+				}
+				e.evaluateBits(ctx, program, parameters[start:end], results[start:end])
+			}
+		}()
+	}
+
+dispatch:
+	for start := 0; start < len(parameters); start += step {
+		select {
+		case starts <- start:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(starts)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error evaluating expression ID %d in batch: %w", ID, err)
+	}
+
+	return results, nil
+}
+
+// bitParallel reports whether the expression can be evaluated with a
+// logic.BitProgram: its variables are boolean, either unregistered or
+// registered as such, and it's small enough to never exceed the budget.
+func (e *evaluator) bitParallel() bool {
+	if e.budget > 0 && e.node.Size() > e.budget {
+		return false
+	}
+
+	for name := range e.variables {
+		if v, ok := e.schemas[name]; ok && v.Type != VariableTypeBoolean {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evaluateBits evaluates at most logic.BitLanes parameter sets at once with
+// the program, as evaluate does one by one. The sets missing unknown
+// parameters are still evaluated one by one, in three-valued logic.
+func (e *evaluator) evaluateBits(ctx context.Context, program *logic.BitProgram, parameters []map[string]int, results []BatchResult) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	variables := program.Variables()
+	words := make([]uint64, len(variables))
+
+	// lanes holds the parameter sets evaluated by the program.
+	var lanes uint64
+	for i, set := range parameters {
+		bound, complete, err := e.bind(set)
+		if err != nil {
+			results[i] = BatchResult{Result: logic.Unknown, Err: err}
+			continue
+		}
+		if !complete {
+			results[i].Result, results[i].Err = e.evaluateKleene(ctx, bound)
+			continue
+		}
+
+		for j, name := range variables {
+			if bound[name] > 0 {
+				words[j] |= 1 << i
+			}
+		}
+		lanes |= 1 << i
+	}
+
+	res := program.Eval(words)
+	for i := range parameters {
+		if lanes>>i&1 == 1 {
+			results[i].Result = logic.TruthOf(res>>i&1 == 1)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionService_EvaluateBatch(t *testing.T) {
+	ctx := context.Background()
+
+	exp := &repositories.Expression{ID: 1, Value: "x AND (y OR z)"}
+
+	t.Run("returns the results in order with per-item errors", func(t *testing.T) {
+		for _, bddEvaluation := range []bool{false, true} {
+			expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+			options := []ExpressionServiceOption{WithExpressionRepositoryOption(expressionRepositoryMock)}
+			if bddEvaluation {
+				options = append(options, WithBDDEvaluationOption())
+			}
+			expressionService := NewExpressionService(options...)
+
+			expressionRepositoryMock.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+
+			res, err := expressionService.EvaluateBatch(ctx, 1, []map[string]int{
+				{"x": 1, "y": 1, "z": 0},
+				{"x": 0, "y": 1, "z": 1},
+				{"x": 1},
+				{"x": 1, "y": 0, "z": 1},
+			}, BatchEvaluationOptions{Workers: 2})
+			require.NoError(t, err)
+
+			require.Len(t, res, 4)
+			assert.Equal(t, BatchResult{Result: logic.True}, res[0])
+			assert.Equal(t, BatchResult{Result: logic.False}, res[1])
+			assert.Equal(t, logic.Unknown, res[2].Result)
+			assert.EqualError(t, res[2].Err, `missing parameter "y" for the logical expression "x AND (y OR z)"`)
+			assert.Equal(t, BatchResult{Result: logic.True}, res[3])
+
+			expressionRepositoryMock.AssertExpectations(t)
+		}
+	})
+
+	t.Run("applies the evaluation options to every item", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+
+		res, err := expressionService.EvaluateBatch(ctx, 1, []map[string]int{
+			{"x": 1},
+			{"x": 0},
+		}, BatchEvaluationOptions{EvaluationOptions: EvaluationOptions{MissingParameters: MissingParametersUnknown}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []BatchResult{{Result: logic.Unknown}, {Result: logic.False}}, res)
+	})
+
+	t.Run("evaluates many parameter sets", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil).Once()
+
+		parameters := make([]map[string]int, 1000)
+		for i := range parameters {
+			parameters[i] = map[string]int{"x": i & 1, "y": i & 2, "z": i & 4}
+		}
+
+		res, err := expressionService.EvaluateBatch(ctx, 1, parameters, BatchEvaluationOptions{Workers: 8})
+		require.NoError(t, err)
+
+		for i, r := range res {
+			expect := i&1 != 0 && (i&2 != 0 || i&4 != 0)
+			assert.Equal(t, logic.TruthOf(expect), r.Result, fmt.Sprintf("parameter set %d", i))
+		}
+	})
+
+	t.Run("fails when the batch is too large", func(t *testing.T) {
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(&repositories.ExpressionRepositoryMock{}),
+			WithLimitsOption(Limits{MaxBatchSize: 1}),
+		)
+
+		_, err := expressionService.EvaluateBatch(ctx, 1, []map[string]int{{"x": 1}, {"x": 0}}, BatchEvaluationOptions{})
+
+		assert.ErrorIs(t, err, ErrBatchTooLarge)
+		assert.EqualError(t, err, "batch too large: 2 parameter sets, the limit is 1")
+	})
+
+	t.Run("fails when the expression is not found", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(2)).Return(nil, repositories.ErrExpressionNotFound).Once()
+
+		_, err := expressionService.EvaluateBatch(ctx, 2, []map[string]int{{"x": 1}}, BatchEvaluationOptions{})
+
+		assert.Equal(t, repositories.ErrExpressionNotFound, err)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		expressionRepositoryMock.On("GetExpressionByID", canceled, int64(1)).Return(exp, nil).Once()
+
+		_, err := expressionService.EvaluateBatch(canceled, 1, []map[string]int{{"x": 1, "y": 1, "z": 1}}, BatchEvaluationOptions{})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestExpressionService_EvaluateBatchBitParallel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("matches the scalar evaluation", func(t *testing.T) {
+		variables := []string{"a", "b", "c", "d", "e"}
+		policies := []MissingParameterPolicy{
+			MissingParametersFail,
+			MissingParametersFalse,
+			MissingParametersDefault,
+			MissingParametersUnknown,
+		}
+
+		property := func(seed int64) bool {
+			r := rand.New(rand.NewSource(seed))
+
+			exp := &repositories.Expression{ID: 1, Value: randomExpression(r, 4, variables)}
+			opts := EvaluationOptions{
+				MissingParameters: policies[r.Intn(len(policies))],
+				Default:           r.Intn(2),
+				Strict:            r.Intn(4) == 0,
+			}
+
+			// Some parameters are missing, unused or not 0 nor 1.
+			parameters := make([]map[string]int, r.Intn(200)+1)
+			for i := range parameters {
+				parameters[i] = map[string]int{}
+				for _, name := range append(variables, "z") {
+					if r.Intn(8) != 0 {
+						parameters[i][name] = r.Intn(4) - 1
+					}
+				}
+			}
+
+			expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+			expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock)).(*expressionService)
+
+			expressionRepositoryMock.On("GetExpressionByID", ctx, int64(1)).Return(exp, nil)
+
+			e, err := expressionService.prepareEvaluation(ctx, 1, opts)
+			require.NoError(t, err)
+			require.True(t, e.bitParallel())
+
+			res, err := expressionService.EvaluateBatch(ctx, 1, parameters, BatchEvaluationOptions{EvaluationOptions: opts, Workers: 3})
+			require.NoError(t, err)
+
+			for i, set := range parameters {
+				result, err := e.evaluate(ctx, set)
+				if !reflect.DeepEqual(BatchResult{Result: result, Err: err}, res[i]) {
+					t.Logf("%q with %v and %+v: got %+v, want %v %v", exp.Value, set, opts, res[i], result, err)
+					return false
+				}
+			}
+
+			return true
+		}
+
+		assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 200}))
+	})
+
+	t.Run("only applies to boolean expressions within budget", func(t *testing.T) {
+		variableRepositoryMock := &repositories.VariableRepositoryMock{}
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithVariableRegistryOption(variableRepositoryMock),
+			WithLimitsOption(Limits{MaxEvaluationSteps: 5}),
+		).(*expressionService)
+
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(1)).Return(&repositories.Expression{ID: 1, Value: "x AND y"}, nil)
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(2)).Return(&repositories.Expression{ID: 2, Value: "x AND age"}, nil)
+		expressionRepositoryMock.On("GetExpressionByID", ctx, int64(3)).Return(&repositories.Expression{ID: 3, Value: "x AND y AND (x OR y)"}, nil)
+		variableRepositoryMock.On("GetVariablesByName", ctx, []string{"x", "y"}).Return([]repositories.Variable{
+			{Name: "x", Type: VariableTypeBoolean},
+		}, nil)
+		variableRepositoryMock.On("GetVariablesByName", ctx, []string{"age", "x"}).Return([]repositories.Variable{
+			{Name: "age", Type: VariableTypeInteger},
+			{Name: "x", Type: VariableTypeBoolean},
+		}, nil)
+
+		for ID, expect := range map[int64]bool{1: true, 2: false, 3: false} {
+			e, err := expressionService.prepareEvaluation(ctx, ID, EvaluationOptions{})
+			require.NoError(t, err)
+
+			assert.Equal(t, expect, e.bitParallel(), fmt.Sprintf("expression ID %d", ID))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+)
+
+// compiledCacheNodes bounds the total number of nodes of the diagrams kept
+// by the cache of a service.
+const compiledCacheNodes = 1 << 20
+
+// compiledCache keeps the most recently used compiled expressions by value,
+// evicting the least recently used ones once their diagrams hold more than
+// maxNodes nodes in total. The diagrams larger than maxNodes on their own
+// aren't kept.
+type compiledCache struct {
+	mu       sync.Mutex
+	maxNodes int
+	nodes    int
+	entries  map[string]*list.Element
+	// recent holds the entries from the most to the least recently used.
+	recent *list.List
+}
+
+type compiledCacheEntry struct {
+	value    string
+	compiled *compiledExpression
+}
+
+func newCompiledCache(maxNodes int) *compiledCache {
+	return &compiledCache{
+		maxNodes: maxNodes,
+		entries:  map[string]*list.Element{},
+		recent:   list.New(),
+	}
+}
+
+// get returns the compiled expression of the value, if cached.
+func (c *compiledCache) get(value string) (*compiledExpression, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[value]
+	if !ok {
+		return nil, false
+	}
+	c.recent.MoveToFront(elem)
+
+	return elem.Value.(*compiledCacheEntry).compiled, true
+}
+
+// add caches the compiled expression of the value, replacing the cached one.
+func (c *compiledCache) add(value string, compiled *compiledExpression) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[value]; ok {
+		c.remove(elem)
+	}
+
+	if compiled.nodes() > c.maxNodes {
+		return
+	}
+
+	c.entries[value] = c.recent.PushFront(&compiledCacheEntry{value: value, compiled: compiled})
+	c.nodes += compiled.nodes()
+
+	for c.nodes > c.maxNodes {
+		c.remove(c.recent.Back())
+	}
+}
+
+func (c *compiledCache) remove(elem *list.Element) {
+	entry := c.recent.Remove(elem).(*compiledCacheEntry)
+	delete(c.entries, entry.value)
+	c.nodes -= entry.compiled.nodes()
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/bdd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompiledCache(t *testing.T) {
+	// The diagram of a single variable has 3 nodes, terminals included.
+	variable := func(name string) *compiledExpression {
+		m := bdd.New(nil)
+		return &compiledExpression{manager: m, root: m.Var(name)}
+	}
+
+	t.Run("evicts the least recently used expressions", func(t *testing.T) {
+		cache := newCompiledCache(9)
+
+		cache.add("x", variable("x"))
+		cache.add("y", variable("y"))
+		cache.add("z", variable("z"))
+
+		_, ok := cache.get("x")
+		require.True(t, ok)
+
+		cache.add("w", variable("w"))
+
+		for value, cached := range map[string]bool{"x": true, "y": false, "z": true, "w": true} {
+			_, ok := cache.get(value)
+			assert.Equal(t, cached, ok, value)
+		}
+		assert.Equal(t, 9, cache.nodes)
+	})
+
+	t.Run("replaces the cached expressions", func(t *testing.T) {
+		cache := newCompiledCache(9)
+
+		cache.add("x", variable("x"))
+		cache.add("x", &compiledExpression{err: bdd.ErrTooLarge})
+
+		compiled, ok := cache.get("x")
+		require.True(t, ok)
+		assert.ErrorIs(t, compiled.err, bdd.ErrTooLarge)
+		assert.Equal(t, 1, cache.nodes)
+	})
+
+	t.Run("doesn't keep the expressions larger than the cache", func(t *testing.T) {
+		cache := newCompiledCache(2)
+
+		cache.add("x", variable("x"))
+
+		_, ok := cache.get("x")
+		assert.False(t, ok)
+		assert.Zero(t, cache.nodes)
+	})
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/bdd"
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+)
+
+var ErrUnknownMissingParameterPolicy = errors.New("unknown missing parameter policy")
+
+// MissingParameterPolicy decides how the variables absent from the
+// evaluation parameters are evaluated.
+type MissingParameterPolicy string
+
+const (
+	// MissingParametersFail fails the evaluation with a MissingParameterError.
+	MissingParametersFail MissingParameterPolicy = "error"
+	// MissingParametersFalse evaluates absent variables as false.
+	MissingParametersFalse MissingParameterPolicy = "false"
+	// MissingParametersDefault evaluates absent variables as the default of
+	// their registered schema, if any, or else as the declared
+	// EvaluationOptions.Default value.
+	MissingParametersDefault MissingParameterPolicy = "default"
+	// MissingParametersUnknown evaluates absent variables as unknown, in
+	// Kleene's three-valued logic.
+	MissingParametersUnknown MissingParameterPolicy = "unknown"
+)
+
+// LookupMissingParameterPolicy returns the policy with the given name. An
+// empty name is MissingParametersFail.
+func LookupMissingParameterPolicy(name string) (MissingParameterPolicy, error) {
+	switch policy := MissingParameterPolicy(name); policy {
+	case "":
+		return MissingParametersFail, nil
+	case MissingParametersFail, MissingParametersFalse, MissingParametersDefault, MissingParametersUnknown:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownMissingParameterPolicy, name)
+}
+
+type EvaluationOptions struct {
+	// MissingParameters defaults to MissingParametersFail.
+	MissingParameters MissingParameterPolicy
+	// Default is the value of the absent variables under
+	// MissingParametersDefault.
+	Default int
+	// Strict rejects the parameters the expression doesn't use, and reports
+	// every missing parameter instead of only the first. It's always on for
+	// services created with WithStrictEvaluationOption.
+	Strict bool
+}
+
+// MissingParameterError is returned when the parameters of an evaluation
+// lack variables of the expression.
+type MissingParameterError struct {
+	Expression string
+	Parameters []string
+}
+
+func (e *MissingParameterError) Error() string {
+	if len(e.Parameters) == 1 {
+		return fmt.Sprintf("missing parameter %q for the logical expression %q", e.Parameters[0], e.Expression)
+	}
+
+	quoted := make([]string, 0, len(e.Parameters))
+	for _, name := range e.Parameters {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+	return fmt.Sprintf("missing parameters %s for the logical expression %q", strings.Join(quoted, ", "), e.Expression)
+}
+
+// UnknownParameterError is returned by strict evaluations given parameters
+// the expression doesn't use.
+type UnknownParameterError struct {
+	Expression string
+	Parameters []string
+}
+
+func (e *UnknownParameterError) Error() string {
+	quoted := make([]string, 0, len(e.Parameters))
+	for _, name := range e.Parameters {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+
+	noun := "parameter"
+	if len(e.Parameters) > 1 {
+		noun = "parameters"
+	}
+	return fmt.Sprintf("unknown %s %s for the logical expression %q", noun, strings.Join(quoted, ", "), e.Expression)
+}
+
+// WithStrictEvaluationOption makes every evaluation strict.
+func WithStrictEvaluationOption() ExpressionServiceOption {
+	return func(es *expressionService) {
+		es.strictEvaluation = true
+	}
+}
+
+// EvaluateExpressionWithOptions evaluates the expression, handling missing
+// parameters according to the options. The parameters of registered
+// variables must be allowed by their schema. The result is only Unknown
+// under MissingParametersUnknown.
+func (es *expressionService) EvaluateExpressionWithOptions(ctx context.Context, ID int64, parameters map[string]int, opts EvaluationOptions) (logic.Truth, error) {
+	e, err := es.prepareEvaluation(ctx, ID, opts)
+	if err != nil {
+		return logic.Unknown, err
+	}
+
+	return e.evaluate(ctx, parameters)
+}
+
+// evaluator evaluates an expression, parsed and compiled once, against
+// parameter sets. It's safe for concurrent use.
+type evaluator struct {
+	exp       *repositories.Expression
+	node      *logic.Node
+	variables utils.LogicalExpressionParametersSet
+	// compiled is the BDD of the expression under BDD evaluation.
+	compiled *compiledExpression
+	schemas  map[string]repositories.Variable
+
+	policy       MissingParameterPolicy
+	defaultValue int
+	strict       bool
+	budget       int
+}
+
+// prepareEvaluation loads, parses and compiles the expression, and the
+// schemas of its registered variables.
+func (es *expressionService) prepareEvaluation(ctx context.Context, ID int64, opts EvaluationOptions) (*evaluator, error) {
+	policy, err := LookupMissingParameterPolicy(string(opts.MissingParameters))
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := es.GetExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := es.newEvaluator(ctx, exp)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := es.registeredVariables(ctx, exp.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Strict = opts.Strict || es.strictEvaluation
+
+	return e.withOptions(schemas, policy, opts), nil
+}
+
+// newEvaluator parses and compiles the expression. It has no schemas and
+// fails on missing parameters until given options. The expressions whose
+// BDD exceeds the limits are evaluated on their tree.
+func (es *expressionService) newEvaluator(ctx context.Context, exp *repositories.Expression) (*evaluator, error) {
+	node, err := logic.Parse(exp.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expression ID %d: %w", exp.ID, err)
+	}
+
+	e := &evaluator{
+		exp:       exp,
+		node:      node,
+		variables: utils.GetLogicalExpressionParameters(exp.Value),
+		policy:    MissingParametersFail,
+		budget:    es.limits.MaxEvaluationSteps,
+	}
+
+	if es.bddEvaluation {
+		e.compiled, err = es.compile(ctx, exp)
+		if errors.Is(err, bdd.ErrTooLarge) {
+			e.compiled, err = nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating expression %q: %w", exp.Value, err)
+		}
+	}
+
+	return e, nil
+}
+
+// withOptions returns a copy of the evaluator evaluating with the given
+// schemas and options.
+func (e evaluator) withOptions(schemas map[string]repositories.Variable, policy MissingParameterPolicy, opts EvaluationOptions) *evaluator {
+	e.schemas = schemas
+	e.policy = policy
+	e.defaultValue = opts.Default
+	e.strict = opts.Strict
+
+	return &e
+}
+
+func (e *evaluator) evaluate(ctx context.Context, parameters map[string]int) (logic.Truth, error) {
+	parameters, complete, err := e.bind(parameters)
+	if err != nil {
+		return logic.Unknown, err
+	}
+	if !complete {
+		return e.evaluateKleene(ctx, parameters)
+	}
+
+	if e.compiled != nil {
+		if err := ctx.Err(); err != nil {
+			return logic.Unknown, err
+		}
+
+		return logic.TruthOf(e.compiled.manager.Eval(e.compiled.root, e.values(parameters))), nil
+	}
+
+	res, err := e.node.EvalWithBudget(ctx, e.values(parameters), e.budget)
+	if err != nil {
+		return logic.Unknown, fmt.Errorf("error evaluating expression %q: %w", e.exp.Value, err)
+	}
+
+	return logic.TruthOf(res), nil
+}
+
+// bind validates the parameters, and completes the missing ones according
+// to the policy. The parameters aren't complete when missing ones are
+// unknown, under MissingParametersUnknown.
+func (e *evaluator) bind(parameters map[string]int) (map[string]int, bool, error) {
+	if e.strict {
+		if unknown := unknownParameters(e.variables, parameters); len(unknown) > 0 {
+			return nil, false, &UnknownParameterError{Expression: e.exp.Value, Parameters: unknown}
+		}
+	}
+
+	if err := checkParameters(e.schemas, parameters); err != nil {
+		return nil, false, err
+	}
+
+	missing := missingParameters(e.variables, parameters)
+	if len(missing) == 0 {
+		return parameters, true, nil
+	}
+
+	switch e.policy {
+	case MissingParametersFalse, MissingParametersDefault:
+		value := 0
+		if e.policy == MissingParametersDefault {
+			value = e.defaultValue
+		}
+
+		completed := make(map[string]int, len(e.variables))
+		for key := range e.variables {
+			if val, ok := parameters[key]; ok {
+				completed[key] = val
+			}
+		}
+		for _, key := range missing {
+			completed[key] = value
+			if v, ok := e.schemas[key]; ok && v.Default != nil && e.policy == MissingParametersDefault {
+				completed[key] = *v.Default
+			}
+		}
+		return completed, true, nil
+	case MissingParametersUnknown:
+		return parameters, false, nil
+	}
+
+	if !e.strict {
+		missing = missing[:1]
+	}
+	return nil, false, &MissingParameterError{Expression: e.exp.Value, Parameters: missing}
+}
+
+// evaluateKleene evaluates the expression with the absent variables unknown.
+func (e *evaluator) evaluateKleene(ctx context.Context, parameters map[string]int) (logic.Truth, error) {
+	values := make(map[string]logic.Truth, len(e.variables))
+	for key := range e.variables {
+		if value, ok := parameters[key]; ok {
+			values[key] = logic.TruthOf(value > 0)
+		}
+	}
+
+	res, err := e.node.EvalKleene(ctx, values, e.budget)
+	if err != nil {
+		return logic.Unknown, fmt.Errorf("error evaluating expression %q: %w", e.exp.Value, err)
+	}
+
+	return res, nil
+}
+
+// values returns the truth values of the variables of the expression, the
+// other parameters being irrelevant.
+func (e *evaluator) values(parameters map[string]int) map[string]bool {
+	values := make(map[string]bool, len(e.variables))
+	for key := range e.variables {
+		values[key] = parameters[key] > 0
+	}
+
+	return values
+}
+
+// missingParameters returns the variables absent from the parameters,
+// sorted.
+func missingParameters(variables utils.LogicalExpressionParametersSet, parameters map[string]int) []string {
+	var missing []string
+	for key := range variables {
+		if _, ok := parameters[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+// unknownParameters returns the parameters that aren't variables, sorted.
+func unknownParameters(variables utils.LogicalExpressionParametersSet, parameters map[string]int) []string {
+	var unknown []string
+	for key := range parameters {
+		if _, ok := variables[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// checkMissingParameters validates if all expected parameters were provided.
+func checkMissingParameters(exp *repositories.Expression, parameters map[string]int) error {
+	if missing := missingParameters(utils.GetLogicalExpressionParameters(exp.Value), parameters); len(missing) > 0 {
+		return &MissingParameterError{Expression: exp.Value, Parameters: missing[:1]}
+	}
+
+	return nil
+}
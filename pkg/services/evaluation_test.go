@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingParameterError(t *testing.T) {
+	err := &MissingParameterError{Expression: "x AND y", Parameters: []string{"x"}}
+	assert.EqualError(t, err, `missing parameter "x" for the logical expression "x AND y"`)
+
+	err = &MissingParameterError{Expression: "x AND y", Parameters: []string{"x", "y"}}
+	assert.EqualError(t, err, `missing parameters "x", "y" for the logical expression "x AND y"`)
+}
+
+func TestUnknownParameterError(t *testing.T) {
+	err := &UnknownParameterError{Expression: "x AND y", Parameters: []string{"z"}}
+	assert.EqualError(t, err, `unknown parameter "z" for the logical expression "x AND y"`)
+
+	err = &UnknownParameterError{Expression: "x AND y", Parameters: []string{"w", "z"}}
+	assert.EqualError(t, err, `unknown parameters "w", "z" for the logical expression "x AND y"`)
+}
+
+func TestExpressionService_EvaluateExpressionWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		bdd        bool
+		strict     bool
+		expression string
+		parameters map[string]int
+		opts       EvaluationOptions
+		expect     logic.Truth
+		err        string
+	}{
+		{
+			name:       "fails by default",
+			expression: "x AND (y OR z)",
+			parameters: map[string]int{"x": 1},
+			expect:     logic.Unknown,
+			err:        `missing parameter "y" for the logical expression "x AND (y OR z)"`,
+		},
+		{
+			name:       "ignores unknown parameters by default",
+			expression: "x",
+			parameters: map[string]int{"x": 1, "usr": 1},
+			expect:     logic.True,
+		},
+		{
+			name:       "rejects unknown parameters when strict",
+			expression: "x AND y",
+			parameters: map[string]int{"x": 1, "usr": 1, "id": 0},
+			opts:       EvaluationOptions{Strict: true},
+			expect:     logic.Unknown,
+			err:        `unknown parameters "id", "usr" for the logical expression "x AND y"`,
+		},
+		{
+			name:       "reports every missing parameter when strict",
+			expression: "x AND (y OR z)",
+			parameters: map[string]int{"x": 1},
+			opts:       EvaluationOptions{Strict: true},
+			expect:     logic.Unknown,
+			err:        `missing parameters "y", "z" for the logical expression "x AND (y OR z)"`,
+		},
+		{
+			name:       "is strict when the service is",
+			strict:     true,
+			expression: "x",
+			parameters: map[string]int{"x": 1, "usr": 1},
+			expect:     logic.Unknown,
+			err:        `unknown parameter "usr" for the logical expression "x"`,
+		},
+		{
+			name:       "fails with an unknown policy",
+			expression: "x",
+			parameters: map[string]int{"x": 1},
+			opts:       EvaluationOptions{MissingParameters: "ignore"},
+			expect:     logic.Unknown,
+			err:        `unknown missing parameter policy: "ignore"`,
+		},
+		{
+			name:       "evaluates absent variables as false",
+			expression: "x AND NOT y",
+			parameters: map[string]int{"x": 1},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersFalse},
+			expect:     logic.True,
+		},
+		{
+			name:       "evaluates absent variables as the default",
+			expression: "x AND NOT y",
+			parameters: map[string]int{"x": 1},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersDefault, Default: 1},
+			expect:     logic.False,
+		},
+		{
+			name:       "evaluates absent variables as the default with BDDs",
+			bdd:        true,
+			expression: "x AND y",
+			parameters: map[string]int{"x": 1},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersDefault, Default: 1},
+			expect:     logic.True,
+		},
+		{
+			name:       "evaluates absent variables as unknown",
+			expression: "x AND (y OR z)",
+			parameters: map[string]int{"x": 1, "z": 0},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersUnknown},
+			expect:     logic.Unknown,
+		},
+		{
+			name:       "unknown variables can't change a known result",
+			bdd:        true,
+			expression: "x AND (y OR z)",
+			parameters: map[string]int{"x": 1, "z": 1},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersUnknown},
+			expect:     logic.True,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+			options := []ExpressionServiceOption{WithExpressionRepositoryOption(expressionRepositoryMock)}
+			if tc.bdd {
+				options = append(options, WithBDDEvaluationOption())
+			}
+			if tc.strict {
+				options = append(options, WithStrictEvaluationOption())
+			}
+			expressionService := NewExpressionService(options...)
+
+			expressionRepositoryMock.
+				On("GetExpressionByID", ctx, int64(1)).
+				Return(&repositories.Expression{
+					ID:    1,
+					Value: tc.expression,
+				}, nil).
+				Maybe()
+
+			res, err := expressionService.EvaluateExpressionWithOptions(ctx, 1, tc.parameters, tc.opts)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expect, res)
+		})
+	}
+}
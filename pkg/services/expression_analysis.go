@@ -0,0 +1,509 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/bdd"
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+)
+
+var (
+	ErrUnknownGraphSource = errors.New("unknown graph source")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrUnknownVariable    = errors.New("unknown variable")
+)
+
+const (
+	defaultModelsLimit = 100
+	maxModelsLimit     = 1000
+)
+
+// GraphSource is the structure RenderExpressionGraph renders.
+type GraphSource string
+
+const (
+	GraphSourceAST GraphSource = "ast"
+	GraphSourceBDD GraphSource = "bdd"
+)
+
+type AnalysisResult struct {
+	Variables []string
+	// ModelCount is how many assignments of Variables satisfy the expression,
+	// and Fraction is the share of all assignments it represents.
+	ModelCount  *big.Int
+	Fraction    float64
+	Satisfiable bool
+	Tautology   bool
+	// Probability is the probability of the expression being true, when
+	// the probabilities of its variables were given.
+	Probability *float64
+}
+
+type ModelsOptions struct {
+	// Variables projects the models onto a subset of the variables of the
+	// expression. Empty means every variable, in order of appearance.
+	Variables []string
+	// Cursor resumes the enumeration where a previous page stopped.
+	Cursor string
+	Limit  int
+}
+
+type ModelsPage struct {
+	Models []map[string]int
+	// NextCursor is empty when there are no more models.
+	NextCursor string
+}
+
+// modelsCursor is the position of a models page, encoded in the cursor token.
+type modelsCursor struct {
+	Variables []string `json:"v"`
+	After     string   `json:"a"`
+}
+
+// MCDCResult holds the test cases achieving MC/DC coverage of an
+// expression.
+type MCDCResult struct {
+	TestCases []MCDCTestCase
+	Coverage  []MCDCCoverage
+	// Uncoverable lists the variables that can't affect the outcome on
+	// their own.
+	Uncoverable []string
+}
+
+// MCDCTestCase is an evaluation request along with its expected result.
+type MCDCTestCase struct {
+	Parameters map[string]int
+	Expected   bool
+	// Request is the evaluation endpoint path for the parameters.
+	Request string
+}
+
+// MCDCCoverage references the two test cases showing the variable
+// independently affecting the outcome.
+type MCDCCoverage struct {
+	Variable  string
+	FalseCase int
+	TrueCase  int
+}
+
+type EquivalenceResult struct {
+	Equivalent bool
+	// Counterexample holds parameters on which the expressions differ.
+	Counterexample map[string]int
+}
+
+// compiledExpression is the BDD of an expression. It's cached and shared by
+// concurrent requests, so its manager is read-only once built: the
+// operations building nodes must run on a clone, see mutableManager.
+type compiledExpression struct {
+	manager *bdd.Manager
+	root    bdd.Ref
+	// err is why the expression couldn't be compiled within the limits, the
+	// manager being nil then.
+	err error
+}
+
+// mutableManager returns a copy of the manager for the operations building
+// nodes, root being valid in it. The copy is bounded as the compilation was.
+func (c *compiledExpression) mutableManager(ctx context.Context, limits Limits) *bdd.Manager {
+	m := c.manager.Clone()
+	m.Bound(ctx, limits.MaxDiagramNodes)
+
+	return m
+}
+
+// nodes returns the weight of the compiled expression in the cache.
+func (c *compiledExpression) nodes() int {
+	if c.manager == nil {
+		return 1
+	}
+	return c.manager.NodeCount()
+}
+
+// compile returns the BDD of the expression, compiling it on first use. It
+// fails with bdd.ErrTooLarge when the diagram exceeds the limits.
+func (es *expressionService) compile(ctx context.Context, exp *repositories.Expression) (*compiledExpression, error) {
+	compiled, ok := es.compiled.get(exp.Value)
+	if !ok {
+		node, err := logic.Parse(exp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expression %q: %w", exp.Value, err)
+		}
+
+		m, root, err := bdd.Compile(ctx, node, es.limits.MaxDiagramNodes)
+		if err != nil && !errors.Is(err, bdd.ErrTooLarge) {
+			return nil, fmt.Errorf("error compiling expression %q: %w", exp.Value, err)
+		}
+
+		// The expressions too large to compile are cached as well, so
+		// they're only compiled once.
+		compiled = &compiledExpression{manager: m, root: root, err: err}
+		es.compiled.add(exp.Value, compiled)
+	}
+
+	if compiled.err != nil {
+		return nil, fmt.Errorf("error compiling expression %q: %w", exp.Value, compiled.err)
+	}
+
+	return compiled, nil
+}
+
+func toBooleans(parameters map[string]int) map[string]bool {
+	values := make(map[string]bool, len(parameters))
+	for key, value := range parameters {
+		values[key] = value > 0
+	}
+	return values
+}
+
+func toIntegers(values map[string]bool) map[string]int {
+	parameters := make(map[string]int, len(values))
+	for key, value := range values {
+		parameters[key] = 0
+		if value {
+			parameters[key] = 1
+		}
+	}
+	return parameters
+}
+
+func (es *expressionService) RenderExpressionGraph(ctx context.Context, ID int64, format logic.GraphFormat, source GraphSource, parameters map[string]int) (string, error) {
+	exp, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return "", err
+	}
+
+	var values map[string]bool
+	if parameters != nil {
+		if err := checkMissingParameters(exp, parameters); err != nil {
+			return "", err
+		}
+		values = toBooleans(parameters)
+	}
+
+	var graph *logic.Graph
+	switch source {
+	case GraphSourceAST, "":
+		graph = logic.NewGraph(node, values)
+	case GraphSourceBDD:
+		compiled, err := es.compile(ctx, exp)
+		if err != nil {
+			return "", err
+		}
+		graph = compiled.manager.Graph(compiled.root, values)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownGraphSource, source)
+	}
+
+	rendered, err := graph.Render(format)
+	if err != nil {
+		return "", fmt.Errorf("error rendering expression ID %d: %w", ID, err)
+	}
+
+	return rendered, nil
+}
+
+func (es *expressionService) CheckEquivalence(ctx context.Context, ID, otherID int64) (*EquivalenceResult, error) {
+	_, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, otherNode, err := es.getParsedExpression(ctx, otherID)
+	if err != nil {
+		return nil, err
+	}
+
+	equivalent, counterexample, err := bdd.Equivalent(ctx, node, otherNode, es.limits.MaxDiagramNodes)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing expression IDs %d and %d: %w", ID, otherID, err)
+	}
+	if equivalent {
+		return &EquivalenceResult{Equivalent: true}, nil
+	}
+
+	return &EquivalenceResult{
+		Equivalent:     false,
+		Counterexample: toIntegers(counterexample),
+	}, nil
+}
+
+// ExpressionDetails is an expression with the metadata derived from it.
+type ExpressionDetails struct {
+	repositories.Expression
+	// Variables are in order of appearance.
+	Variables []string
+	// Canonical is the expression in the canonical dialect on a single line,
+	// however it was formatted.
+	Canonical string
+	// Satisfiable and Tautology are nil when the expression is too complex
+	// to compile within the limits.
+	Satisfiable *bool
+	Tautology   *bool
+}
+
+// DescribeExpression returns the expression with its variables, canonical
+// form, and whether it's satisfiable or a tautology when that can be decided
+// within the limits.
+func (es *expressionService) DescribeExpression(ctx context.Context, ID int64) (*ExpressionDetails, error) {
+	exp, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &ExpressionDetails{
+		Expression: *exp,
+		Variables:  node.Variables(),
+		Canonical:  node.String(),
+	}
+
+	compiled, err := es.compile(ctx, exp)
+	if errors.Is(err, bdd.ErrTooLarge) {
+		return details, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	satisfiable, tautology := compiled.root != bdd.False, compiled.root == bdd.True
+	details.Satisfiable, details.Tautology = &satisfiable, &tautology
+
+	return details, nil
+}
+
+// AnalyzeExpression counts the models of the expression and, when the
+// probability of each variable being true is given, computes the probability
+// of the expression being true assuming the variables are independent.
+func (es *expressionService) AnalyzeExpression(ctx context.Context, ID int64, probabilities map[string]float64) (*AnalysisResult, error) {
+	exp, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := es.compile(ctx, exp)
+	if err != nil {
+		return nil, err
+	}
+
+	m, root := compiled.manager, compiled.root
+
+	count := m.SatCount(root)
+	total := new(big.Int).Lsh(big.NewInt(1), uint(len(m.Variables())))
+	fraction, _ := new(big.Float).Quo(new(big.Float).SetInt(count), new(big.Float).SetInt(total)).Float64()
+
+	res := &AnalysisResult{
+		Variables:   node.Variables(),
+		ModelCount:  count,
+		Fraction:    fraction,
+		Satisfiable: root != bdd.False,
+		Tautology:   root == bdd.True,
+	}
+
+	if probabilities != nil {
+		p, err := m.Probability(root, probabilities)
+		if err != nil {
+			return nil, err
+		}
+		res.Probability = &p
+	}
+
+	return res, nil
+}
+
+// ListModels enumerates the parameters satisfying the expression, a page at a
+// time. Pages are in lexicographic order of the projected variables.
+func (es *expressionService) ListModels(ctx context.Context, ID int64, opts ModelsOptions) (*ModelsPage, error) {
+	exp, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := node.Variables()
+	if len(opts.Variables) > 0 {
+		// The variables repeated in the projection are only kept once.
+		vars = make([]string, 0, len(opts.Variables))
+		seen := make(map[string]struct{}, len(opts.Variables))
+
+		known := utils.GetLogicalExpressionParameters(exp.Value)
+		for _, name := range opts.Variables {
+			if _, ok := known[name]; !ok {
+				return nil, fmt.Errorf("%w %q for the logical expression %q", ErrUnknownVariable, name, exp.Value)
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			vars = append(vars, name)
+		}
+	}
+
+	var after []bool
+	if opts.Cursor != "" {
+		after, err = decodeModelsCursor(opts.Cursor, vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultModelsLimit
+	}
+	if limit > maxModelsLimit {
+		limit = maxModelsLimit
+	}
+
+	compiled, err := es.compile(ctx, exp)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra model tells whether there is a next page.
+	m := compiled.mutableManager(ctx, es.limits)
+	models := m.Models(compiled.root, vars, after, limit+1)
+	if err := m.Err(); err != nil {
+		return nil, fmt.Errorf("error listing the models of expression ID %d: %w", ID, err)
+	}
+
+	page := &ModelsPage{Models: make([]map[string]int, 0, limit)}
+	if len(models) > limit {
+		models = models[:limit]
+		page.NextCursor = encodeModelsCursor(vars, models[limit-1])
+	}
+
+	for _, model := range models {
+		parameters := make(map[string]int, len(vars))
+		for i, name := range vars {
+			parameters[name] = 0
+			if model[i] {
+				parameters[name] = 1
+			}
+		}
+		page.Models = append(page.Models, parameters)
+	}
+
+	return page, nil
+}
+
+func encodeModelsCursor(vars []string, after []bool) string {
+	var sb strings.Builder
+	for _, value := range after {
+		if value {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+
+	raw, _ := json.Marshal(modelsCursor{Variables: vars, After: sb.String()})
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeModelsCursor(cursor string, vars []string) ([]bool, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c modelsCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// A cursor is only valid for the projection it was created with.
+	if strings.Join(c.Variables, ",") != strings.Join(vars, ",") || len(c.After) != len(vars) {
+		return nil, ErrInvalidCursor
+	}
+
+	after := make([]bool, 0, len(c.After))
+	for _, bit := range c.After {
+		switch bit {
+		case '0':
+			after = append(after, false)
+		case '1':
+			after = append(after, true)
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return after, nil
+}
+
+// GenerateMCDC returns a small set of test cases achieving modified
+// condition/decision coverage of the expression.
+func (es *expressionService) GenerateMCDC(ctx context.Context, ID int64) (*MCDCResult, error) {
+	exp, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := es.compile(ctx, exp)
+	if err != nil {
+		return nil, err
+	}
+
+	m := compiled.mutableManager(ctx, es.limits)
+	mcdc := m.MCDC(compiled.root, node.Variables())
+	if err := m.Err(); err != nil {
+		return nil, fmt.Errorf("error generating the MC/DC cases of expression ID %d: %w", ID, err)
+	}
+
+	res := &MCDCResult{
+		TestCases:   make([]MCDCTestCase, 0, len(mcdc.Vectors)),
+		Coverage:    make([]MCDCCoverage, 0, len(mcdc.Pairs)),
+		Uncoverable: mcdc.Uncoverable,
+	}
+
+	for _, vector := range mcdc.Vectors {
+		parameters := toIntegers(vector)
+
+		query := url.Values{}
+		for key, value := range parameters {
+			query.Set(key, strconv.Itoa(value))
+		}
+
+		res.TestCases = append(res.TestCases, MCDCTestCase{
+			Parameters: parameters,
+			Expected:   m.Eval(compiled.root, vector),
+			Request:    fmt.Sprintf("/evaluate/%d?%s", ID, query.Encode()),
+		})
+	}
+
+	for _, pair := range mcdc.Pairs {
+		res.Coverage = append(res.Coverage, MCDCCoverage{
+			Variable:  pair.Variable,
+			FalseCase: pair.FalseVector,
+			TrueCase:  pair.TrueVector,
+		})
+	}
+
+	return res, nil
+}
+
+// KarnaughMap lays out the expression as a Karnaugh map, along with the
+// prime implicants found by the minimizer.
+func (es *expressionService) KarnaughMap(ctx context.Context, ID int64) (*logic.KarnaughMap, error) {
+	_, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := logic.NewKarnaughMap(node)
+	if err != nil {
+		return nil, fmt.Errorf("error building the Karnaugh map of expression ID %d: %w", ID, err)
+	}
+
+	return k, nil
+}
@@ -0,0 +1,608 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/bdd"
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
+func TestExpressionService_RenderExpressionGraph(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when a parameter of the trace is missing", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		graph, err := expressionService.RenderExpressionGraph(ctx, 1, logic.GraphFormatMermaid, GraphSourceAST, map[string]int{"x": 1})
+
+		assert.EqualError(t, err, `missing parameter "y" for the logical expression "x OR y"`)
+		assert.Empty(t, graph)
+	})
+
+	t.Run("renders the expression correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Twice()
+
+		graph, err := expressionService.RenderExpressionGraph(ctx, 1, logic.GraphFormatMermaid, GraphSourceAST, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "graph TD\n  n0[\"OR\"]\n  n1[\"x\"]\n  n2[\"y\"]\n  n0 --> n1\n  n0 --> n2\n", graph)
+
+		graph, err = expressionService.RenderExpressionGraph(ctx, 1, logic.GraphFormatMermaid, GraphSourceAST, map[string]int{"x": 0, "y": 2})
+		require.NoError(t, err)
+
+		assert.Contains(t, graph, "class n0,n2 true\n  class n1 false\n")
+	})
+
+	t.Run("renders the BDD of the expression correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		graph, err := expressionService.RenderExpressionGraph(ctx, 1, logic.GraphFormatDOT, GraphSourceBDD, nil)
+		require.NoError(t, err)
+
+		expect := `digraph expression {
+  n0 [label="x"];
+  n1 [label="y"];
+  n2 [label="0"];
+  n3 [label="1"];
+  n1 -> n2 [label="0", style=dashed];
+  n1 -> n3 [label="1"];
+  n0 -> n1 [label="0", style=dashed];
+  n0 -> n3 [label="1"];
+}
+`
+		assert.Equal(t, expect, graph)
+	})
+
+	t.Run("returns error when the source is unknown", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		graph, err := expressionService.RenderExpressionGraph(ctx, 1, logic.GraphFormatDOT, "zdd", nil)
+
+		assert.EqualError(t, err, `unknown graph source: "zdd"`)
+		assert.Empty(t, graph)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_CheckEquivalence(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when an expression is not found", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(2)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		res, err := expressionService.CheckEquivalence(ctx, 1, 2)
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("checks equivalence correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "NOT (x OR y)",
+			}, nil).
+			Twice()
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(2)).
+			Return(&repositories.Expression{
+				ID:    2,
+				Value: "NOT x AND NOT y",
+			}, nil).
+			Once()
+
+		res, err := expressionService.CheckEquivalence(ctx, 1, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, &EquivalenceResult{Equivalent: true}, res)
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(3)).
+			Return(&repositories.Expression{
+				ID:    3,
+				Value: "NOT x",
+			}, nil).
+			Once()
+
+		res, err = expressionService.CheckEquivalence(ctx, 1, 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, &EquivalenceResult{
+			Equivalent:     false,
+			Counterexample: map[string]int{"x": 0, "y": 1},
+		}, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_DescribeExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		res, err := expressionService.DescribeExpression(ctx, 1)
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, res)
+	})
+
+	testCases := []struct {
+		name   string
+		exp    repositories.Expression
+		expect ExpressionDetails
+	}{
+		{
+			name: "describes a formatted expression",
+			exp: repositories.Expression{
+				ID:        1,
+				Value:     "(y OR x)\nAND NOT (z)",
+				CreatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
+				Version:   3,
+			},
+			expect: ExpressionDetails{
+				Variables:   []string{"y", "x", "z"},
+				Canonical:   "(y OR x) AND NOT z",
+				Satisfiable: boolPointer(true),
+				Tautology:   boolPointer(false),
+			},
+		},
+		{
+			name:   "describes a tautology",
+			exp:    repositories.Expression{ID: 1, Value: "x OR NOT x", Version: 1},
+			expect: ExpressionDetails{Variables: []string{"x"}, Canonical: "x OR NOT x", Satisfiable: boolPointer(true), Tautology: boolPointer(true)},
+		},
+		{
+			name:   "describes a contradiction",
+			exp:    repositories.Expression{ID: 1, Value: "x AND NOT x", Version: 1},
+			expect: ExpressionDetails{Variables: []string{"x"}, Canonical: "x AND NOT x", Satisfiable: boolPointer(false), Tautology: boolPointer(false)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exp := tc.exp
+			expressionRepositoryMock.
+				On("GetExpressionByID", ctx, int64(1)).
+				Return(&exp, nil).
+				Once()
+
+			res, err := expressionService.DescribeExpression(ctx, 1)
+			require.NoError(t, err)
+
+			tc.expect.Expression = tc.exp
+			assert.Equal(t, &tc.expect, res)
+		})
+	}
+
+	t.Run("leaves undecided what exceeds the limits", func(t *testing.T) {
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithLimitsOption(Limits{MaxDiagramNodes: 4}),
+		)
+
+		exp := repositories.Expression{ID: 1, Value: "(x AND y) OR (z AND k)"}
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&exp, nil).
+			Once()
+
+		res, err := expressionService.DescribeExpression(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, &ExpressionDetails{
+			Expression: exp,
+			Variables:  []string{"x", "y", "z", "k"},
+			Canonical:  "x AND y OR z AND k",
+		}, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_AnalyzeExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		res, err := expressionService.AnalyzeExpression(ctx, 1, nil)
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns error when a probability is missing", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		res, err := expressionService.AnalyzeExpression(ctx, 1, map[string]float64{"x": 0.5})
+
+		assert.EqualError(t, err, "invalid probability: missing probability of y")
+		assert.Nil(t, res)
+	})
+
+	t.Run("analyzes the expression correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "(x OR y) AND z",
+			}, nil).
+			Twice()
+
+		res, err := expressionService.AnalyzeExpression(ctx, 1, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, &AnalysisResult{
+			Variables:   []string{"x", "y", "z"},
+			ModelCount:  big.NewInt(3),
+			Fraction:    0.375,
+			Satisfiable: true,
+			Tautology:   false,
+		}, res)
+
+		res, err = expressionService.AnalyzeExpression(ctx, 1, map[string]float64{"x": 0.5, "y": 0.5, "z": 0.4})
+		require.NoError(t, err)
+
+		require.NotNil(t, res.Probability)
+		assert.InDelta(t, 0.3, *res.Probability, 1e-9)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_ListModels(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	expressionRepositoryMock.
+		On("GetExpressionByID", ctx, int64(1)).
+		Return(&repositories.Expression{
+			ID:    1,
+			Value: "x OR y AND z",
+		}, nil)
+
+	t.Run("returns error when a projected variable is unknown", func(t *testing.T) {
+		page, err := expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"x", "w"}})
+
+		assert.EqualError(t, err, `unknown variable "w" for the logical expression "x OR y AND z"`)
+		assert.Nil(t, page)
+	})
+
+	t.Run("returns error when the cursor is invalid", func(t *testing.T) {
+		page, err := expressionService.ListModels(ctx, 1, ModelsOptions{Cursor: "invalid"})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, page)
+
+		page, err = expressionService.ListModels(ctx, 1, ModelsOptions{Limit: 1})
+		require.NoError(t, err)
+
+		// The cursor doesn't apply to another projection.
+		page, err = expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"x"}, Cursor: page.NextCursor})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, page)
+	})
+
+	t.Run("paginates every model", func(t *testing.T) {
+		page, err := expressionService.ListModels(ctx, 1, ModelsOptions{Limit: 3})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]int{
+			{"x": 0, "y": 1, "z": 1},
+			{"x": 1, "y": 0, "z": 0},
+			{"x": 1, "y": 0, "z": 1},
+		}, page.Models)
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = expressionService.ListModels(ctx, 1, ModelsOptions{Limit: 3, Cursor: page.NextCursor})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]int{
+			{"x": 1, "y": 1, "z": 0},
+			{"x": 1, "y": 1, "z": 1},
+		}, page.Models)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("projects the models", func(t *testing.T) {
+		page, err := expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"z"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]int{{"z": 0}, {"z": 1}}, page.Models)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("ignores the repeated projected variables", func(t *testing.T) {
+		page, err := expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"z", "x", "z"}, Limit: 3})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]int{{"z": 0, "x": 1}, {"z": 1, "x": 0}, {"z": 1, "x": 1}}, page.Models)
+		assert.Empty(t, page.NextCursor)
+
+		page, err = expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"z", "x"}, Limit: 1})
+		require.NoError(t, err)
+
+		// The cursor applies to the same projection, repeated or not.
+		page, err = expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"z", "z", "x"}, Cursor: page.NextCursor})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]int{{"z": 1, "x": 0}, {"z": 1, "x": 1}}, page.Models)
+	})
+}
+
+func TestExpressionService_GenerateMCDC(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		res, err := expressionService.GenerateMCDC(ctx, 1)
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("generates the test cases correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		res, err := expressionService.GenerateMCDC(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, &MCDCResult{
+			TestCases: []MCDCTestCase{
+				{
+					Parameters: map[string]int{"x": 0, "y": 1},
+					Expected:   false,
+					Request:    "/evaluate/1?x=0&y=1",
+				},
+				{
+					Parameters: map[string]int{"x": 1, "y": 1},
+					Expected:   true,
+					Request:    "/evaluate/1?x=1&y=1",
+				},
+				{
+					Parameters: map[string]int{"x": 1, "y": 0},
+					Expected:   false,
+					Request:    "/evaluate/1?x=1&y=0",
+				},
+			},
+			Coverage: []MCDCCoverage{
+				{Variable: "x", FalseCase: 0, TrueCase: 1},
+				{Variable: "y", FalseCase: 2, TrueCase: 1},
+			},
+			Uncoverable: []string{},
+		}, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_AnalysisConcurrency(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	// The requests share the cached BDD of the expression, which must be
+	// left as is: run with -race to catch them building nodes in it.
+	expressionRepositoryMock.
+		On("GetExpressionByID", ctx, int64(1)).
+		Return(&repositories.Expression{ID: 1, Value: "(a AND b) OR (c AND NOT d) OR (e AND f)"}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := expressionService.ListModels(ctx, 1, ModelsOptions{Variables: []string{"a", "c", "e"}})
+			assert.NoError(t, err)
+			assert.Len(t, res.Models, 7)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			res, err := expressionService.GenerateMCDC(ctx, 1)
+			assert.NoError(t, err)
+			assert.Empty(t, res.Uncoverable)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExpressionService_DiagramLimits(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(
+		WithExpressionRepositoryOption(expressionRepositoryMock),
+		WithLimitsOption(Limits{MaxDiagramNodes: 4}),
+		WithBDDEvaluationOption(),
+	)
+
+	ctx := context.Background()
+
+	expressionRepositoryMock.
+		On("GetExpressionByID", ctx, int64(1)).
+		Return(&repositories.Expression{ID: 1, Value: "(a AND b) OR (c AND d)"}, nil)
+
+	t.Run("fails the analyses of the expressions too large to compile", func(t *testing.T) {
+		_, err := expressionService.AnalyzeExpression(ctx, 1, nil)
+		assert.ErrorIs(t, err, bdd.ErrTooLarge)
+
+		_, err = expressionService.ListModels(ctx, 1, ModelsOptions{})
+		assert.ErrorIs(t, err, bdd.ErrTooLarge)
+
+		_, err = expressionService.GenerateMCDC(ctx, 1)
+		assert.ErrorIs(t, err, bdd.ErrTooLarge)
+
+		_, err = expressionService.CheckEquivalence(ctx, 1, 1)
+		assert.ErrorIs(t, err, bdd.ErrTooLarge)
+	})
+
+	t.Run("evaluates the expressions too large to compile on their tree", func(t *testing.T) {
+		res, err := expressionService.EvaluateExpression(ctx, 1, map[string]int{"a": 0, "b": 1, "c": 1, "d": 1})
+		require.NoError(t, err)
+		assert.True(t, res)
+	})
+}
+
+func TestExpressionService_Compile(t *testing.T) {
+	es := NewExpressionService().(*expressionService)
+
+	t.Run("stops compiling when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Every x variable is ordered above every y variable, so the
+		// diagram grows exponentially with the pairs and the context is
+		// checked before it's built.
+		xs := make([]string, 0, 12)
+		terms := make([]string, 0, 12)
+		for i := 0; i < 12; i++ {
+			xs = append(xs, fmt.Sprintf("x%c", 'a'+i))
+			terms = append(terms, fmt.Sprintf("(x%[1]c AND y%[1]c)", 'a'+i))
+		}
+		value := fmt.Sprintf("((%s) AND FALSE) OR (%s AND FALSE) OR %s",
+			strings.Join(xs, " OR "), strings.Join(xs, " AND "), strings.Join(terms, " OR "))
+
+		_, err := es.compile(ctx, &repositories.Expression{Value: value})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestExpressionService_KarnaughMap(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the expression has too many variables", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "a AND b AND c AND d AND e AND f AND g",
+			}, nil).
+			Once()
+
+		k, err := expressionService.KarnaughMap(ctx, 1)
+
+		assert.ErrorIs(t, err, logic.ErrTooManyVariables)
+		assert.Nil(t, k)
+	})
+
+	t.Run("builds the map correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x OR y",
+			}, nil).
+			Once()
+
+		k, err := expressionService.KarnaughMap(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]bool{{false, true}, {true, true}}, k.Cells)
+		assert.Equal(t, []logic.KarnaughGroup{
+			{Term: "y", Selected: true, Cells: [][2]int{{0, 1}, {1, 1}}},
+			{Term: "x", Selected: true, Cells: [][2]int{{1, 0}, {1, 1}}},
+		}, k.Groups)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
@@ -4,34 +4,122 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
 	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
-	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
 )
 
 var ErrInvalidExpression = errors.New("invalid expression")
 
 type ExpressionService interface {
-	CreateExpression(ctx context.Context, exp *repositories.Expression) (*repositories.Expression, error)
+	CreateExpression(ctx context.Context, exp *repositories.Expression, options ...CreateExpressionOption) (*repositories.Expression, error)
 	ListExpressions(ctx context.Context) ([]repositories.Expression, error)
-	UpdateExpression(ctx context.Context, exp *repositories.Expression) (*repositories.Expression, error)
+	GetExpression(ctx context.Context, ID int64) (*repositories.Expression, error)
+	DescribeExpression(ctx context.Context, ID int64) (*ExpressionDetails, error)
+	UpdateExpression(ctx context.Context, exp *repositories.Expression, options ...UpdateExpressionOption) (*repositories.Expression, error)
+	DeleteExpression(ctx context.Context, ID int64, options ...DeleteExpressionOption) error
+	RestoreExpression(ctx context.Context, ID int64) (*repositories.Expression, error)
 	EvaluateExpression(ctx context.Context, ID int64, parameters map[string]int) (bool, error)
+	EvaluateExpressionWithOptions(ctx context.Context, ID int64, parameters map[string]int, opts EvaluationOptions) (logic.Truth, error)
+	EvaluateBatch(ctx context.Context, ID int64, parameters []map[string]int, opts BatchEvaluationOptions) ([]BatchResult, error)
+	FormatExpression(ctx context.Context, exp *repositories.Expression, opts logic.FormatOptions) (string, error)
+	CompileExpressionToSQL(ctx context.Context, ID int64, opts logic.SQLOptions) (*logic.SQLFragment, error)
+	RenderExpressionGraph(ctx context.Context, ID int64, format logic.GraphFormat, source GraphSource, parameters map[string]int) (string, error)
+	CheckEquivalence(ctx context.Context, ID, otherID int64) (*EquivalenceResult, error)
+	AnalyzeExpression(ctx context.Context, ID int64, probabilities map[string]float64) (*AnalysisResult, error)
+	ListModels(ctx context.Context, ID int64, opts ModelsOptions) (*ModelsPage, error)
+	GenerateMCDC(ctx context.Context, ID int64) (*MCDCResult, error)
+	KarnaughMap(ctx context.Context, ID int64) (*logic.KarnaughMap, error)
+	ExportNetlist(ctx context.Context, ID int64, format logic.NetlistFormat) (string, error)
+	GenerateGo(ctx context.Context, ID int64, opts logic.GoOptions) (string, error)
+	SynthesizeExpression(ctx context.Context, opts SynthesisOptions) (*SynthesisResult, error)
+	ListExpressionsByVariable(ctx context.Context, name string) ([]repositories.Expression, error)
+	RenameVariable(ctx context.Context, from, to string) ([]repositories.Expression, error)
+	MatchExpressions(ctx context.Context, parameters map[string]int, opts MatchOptions) ([]int64, error)
+	Limits() Limits
 }
 
 type expressionService struct {
 	expressionRepository repositories.ExpressionRepository
+
+	// bddEvaluation makes EvaluateExpression run on the compiled BDDs of
+	// the expressions, cached by expression value.
+	bddEvaluation bool
+	// compiled caches the recently compiled expressions by value. They're
+	// shared by concurrent requests, see compiledExpression.
+	compiled *compiledCache
+
+	// strictEvaluation makes every evaluation strict, see
+	// EvaluationOptions.Strict.
+	strictEvaluation bool
+
+	limits Limits
+
+	// variableRepository holds the schemas of the registered variables.
+	variableRepository repositories.VariableRepository
+
+	// matching indexes the expressions for MatchExpressions, and is reloaded
+	// once older than matchIndexTTL.
+	matching      matchIndex
+	matchIndexTTL time.Duration
+}
+
+type createExpressionOptions struct {
+	format              *logic.FormatOptions
+	registeredVariables bool
+}
+
+type CreateExpressionOption func(o *createExpressionOptions)
+
+// WithFormatOption makes CreateExpression store the expression formatted.
+func WithFormatOption(opts logic.FormatOptions) CreateExpressionOption {
+	return func(o *createExpressionOptions) {
+		o.format = &opts
+	}
 }
 
-func (es *expressionService) CreateExpression(ctx context.Context, exp *repositories.Expression) (*repositories.Expression, error) {
-	if err := validateExpression(exp.Value); err != nil {
+func (es *expressionService) CreateExpression(ctx context.Context, exp *repositories.Expression, options ...CreateExpressionOption) (*repositories.Expression, error) {
+	createOptions := &createExpressionOptions{}
+	for _, option := range options {
+		option(createOptions)
+	}
+
+	submitted := exp.Value
+	if err := normalizeExpression(exp); err != nil {
 		return nil, err
 	}
 
+	if createOptions.format != nil {
+		node, err := logic.Parse(exp.Value)
+		if err != nil {
+			return nil, ErrInvalidExpression
+		}
+
+		exp.Value = logic.Format(node, *createOptions.format)
+		if exp.Value != submitted {
+			exp.Original = submitted
+		}
+	}
+
+	if err := es.validateExpression(exp.Value); err != nil {
+		return nil, err
+	}
+
+	if createOptions.registeredVariables {
+		if err := es.checkRegisteredVariables(ctx, exp.Value); err != nil {
+			return nil, err
+		}
+	}
+
 	exp, err := es.expressionRepository.CreateExpression(ctx, exp)
 	if err != nil {
 		return nil, fmt.Errorf("error creating expression: %w", err)
 	}
 
+	es.indexExpression(exp)
+
 	return exp, nil
 }
 
@@ -41,19 +129,60 @@ func (es *expressionService) ListExpressions(ctx context.Context) ([]repositorie
 		return nil, fmt.Errorf("error getting all expressions: %w", err)
 	}
 
+	for i := range exps {
+		upgradeLegacyExpression(&exps[i])
+	}
+
 	return exps, nil
 }
 
-func (es *expressionService) UpdateExpression(ctx context.Context, exp *repositories.Expression) (*repositories.Expression, error) {
+func (es *expressionService) GetExpression(ctx context.Context, ID int64) (*repositories.Expression, error) {
+	exp, err := es.expressionRepository.GetExpressionByID(ctx, ID)
+	if err == repositories.ErrExpressionNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting expression ID %d: %w", ID, err)
+	}
+
+	upgradeLegacyExpression(exp)
+
+	return exp, nil
+}
+
+type updateExpressionOptions struct {
+	keepNamespace bool
+}
+
+type UpdateExpressionOption func(o *updateExpressionOptions)
+
+// WithKeptNamespaceOption makes UpdateExpression leave the expression in its
+// stored namespace, whatever the namespace given.
+func WithKeptNamespaceOption() UpdateExpressionOption {
+	return func(o *updateExpressionOptions) {
+		o.keepNamespace = true
+	}
+}
+
+func (es *expressionService) UpdateExpression(ctx context.Context, exp *repositories.Expression, options ...UpdateExpressionOption) (*repositories.Expression, error) {
+	var updateOptions updateExpressionOptions
+	for _, option := range options {
+		option(&updateOptions)
+	}
+
 	if exp.ID == 0 {
 		return nil, fmt.Errorf("invalid expression ID provided")
 	}
 
-	if err := validateExpression(exp.Value); err != nil {
+	if err := normalizeExpression(exp); err != nil {
 		return nil, err
 	}
 
-	updatedExp, err := es.expressionRepository.UpdateExpression(ctx, exp)
+	if err := es.validateExpression(exp.Value); err != nil {
+		return nil, err
+	}
+
+	updatedExp, err := es.expressionRepository.UpdateExpression(ctx, exp, updateOptions.keepNamespace)
 	if err == repositories.ErrNoRowsAffected {
 		return nil, err
 	}
@@ -61,51 +190,240 @@ func (es *expressionService) UpdateExpression(ctx context.Context, exp *reposito
 		return nil, fmt.Errorf("error updating expression ID %d: %w", exp.ID, err)
 	}
 
+	es.indexExpression(updatedExp)
+
 	return updatedExp, nil
 }
 
-func validateExpression(expression string) error {
-	if expression == "" {
-		return fmt.Errorf("value can't be empty")
+type deleteExpressionOptions struct {
+	hard bool
+}
+
+type DeleteExpressionOption func(o *deleteExpressionOptions)
+
+// WithHardDeleteOption makes DeleteExpression delete the expression for
+// good, even when it's already soft deleted, instead of soft deleting it.
+func WithHardDeleteOption() DeleteExpressionOption {
+	return func(o *deleteExpressionOptions) {
+		o.hard = true
 	}
+}
 
-	isValid := utils.IsLogicalExpressionValid(expression)
-	if !isValid {
-		return ErrInvalidExpression
+// DeleteExpression soft deletes the expression, which can then be restored
+// with RestoreExpression, unless WithHardDeleteOption is given.
+func (es *expressionService) DeleteExpression(ctx context.Context, ID int64, options ...DeleteExpressionOption) error {
+	deleteOptions := &deleteExpressionOptions{}
+	for _, option := range options {
+		option(deleteOptions)
 	}
 
+	var err error
+	if deleteOptions.hard {
+		err = es.expressionRepository.HardDeleteExpression(ctx, ID)
+	} else {
+		err = es.expressionRepository.DeleteExpression(ctx, ID)
+	}
+	if err == repositories.ErrExpressionNotFound {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting expression ID %d: %w", ID, err)
+	}
+
+	es.unindexExpression(ID)
+
 	return nil
 }
 
-func (es *expressionService) EvaluateExpression(ctx context.Context, ID int64, parameters map[string]int) (bool, error) {
-	exp, err := es.expressionRepository.GetExpressionByID(ctx, ID)
+// RestoreExpression restores a soft deleted expression. It fails with
+// repositories.ErrExpressionNotFound when the expression isn't soft deleted.
+func (es *expressionService) RestoreExpression(ctx context.Context, ID int64) (*repositories.Expression, error) {
+	exp, err := es.expressionRepository.RestoreExpression(ctx, ID)
 	if err == repositories.ErrExpressionNotFound {
-		return false, err
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error restoring expression ID %d: %w", ID, err)
+	}
+
+	upgradeLegacyExpression(exp)
+
+	es.indexExpression(exp)
+
+	return exp, nil
+}
+
+// upgradeLegacyExpression rewrites into the canonical dialect the value of
+// an expression stored before the dialects, in the syntax the first versions
+// of the service accepted, such as x && !y or x AND !y. The stored text is
+// kept in Original, as for the expressions created in a dialect. Values the
+// parser can't read are left as is.
+func upgradeLegacyExpression(exp *repositories.Expression) {
+	// The canonical dialect has none of the symbols of the legacy syntax.
+	if !strings.ContainsAny(exp.Value, "&|!") {
+		return
+	}
+
+	dialect, err := logic.DetectDialect(exp.Value)
+	if err != nil {
+		return
 	}
+
+	canonical, err := logic.Normalize(exp.Value, dialect)
 	if err != nil {
-		return false, fmt.Errorf("error getting expression ID %d: %w", ID, err)
+		return
 	}
 
-	// Validate if all expected parameters were provided
-	expExpectedParameters := utils.GetLogicalExpressionParameters(exp.Value)
-	for key := range expExpectedParameters {
-		if _, ok := parameters[key]; !ok {
-			return false, fmt.Errorf("missing parameter %q for the logical expression %q", key, exp.Value)
+	exp.Value, exp.Original, exp.Dialect = canonical, exp.Value, string(dialect)
+}
+
+// normalizeExpression rewrites the expression value into the canonical
+// dialect. The submitted text is kept in Original when it changes.
+func normalizeExpression(exp *repositories.Expression) error {
+	if exp.Value == "" {
+		return fmt.Errorf("value can't be empty")
+	}
+
+	dialect, err := logic.LookupDialect(exp.Dialect)
+	if err != nil {
+		return err
+	}
+	if dialect == "" {
+		if dialect, err = logic.DetectDialect(exp.Value); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidExpression, err)
 		}
 	}
 
-	res, err := utils.EvaluateLogicalExpression(exp.Value, parameters)
+	canonical, err := logic.Normalize(exp.Value, dialect)
+	if err != nil {
+		return ErrInvalidExpression
+	}
+
+	exp.Original, exp.Dialect = "", ""
+	if canonical != exp.Value {
+		exp.Original = exp.Value
+		exp.Value = canonical
+	}
+	if dialect != logic.DialectCanonical {
+		exp.Dialect = string(dialect)
+	}
+
+	return nil
+}
+
+func (es *expressionService) FormatExpression(ctx context.Context, exp *repositories.Expression, opts logic.FormatOptions) (string, error) {
+	if err := normalizeExpression(exp); err != nil {
+		return "", err
+	}
+
+	node, err := logic.Parse(exp.Value)
+	if err != nil {
+		return "", ErrInvalidExpression
+	}
+
+	return logic.Format(node, opts), nil
+}
+
+func (es *expressionService) CompileExpressionToSQL(ctx context.Context, ID int64, opts logic.SQLOptions) (*logic.SQLFragment, error) {
+	_, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fragment, err := logic.ToSQL(node, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling expression ID %d to SQL: %w", ID, err)
+	}
+
+	return fragment, nil
+}
+
+// ExportNetlist exports the expression as a combinational circuit module
+// named after its ID.
+func (es *expressionService) ExportNetlist(ctx context.Context, ID int64, format logic.NetlistFormat) (string, error) {
+	_, node, err := es.getParsedExpression(ctx, ID)
+	if err != nil {
+		return "", err
+	}
+
+	netlist, err := logic.ExportNetlist(node, format, logic.NetlistOptions{
+		Module: fmt.Sprintf("expression_%d", ID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error exporting expression ID %d: %w", ID, err)
+	}
+
+	return netlist, nil
+}
+
+// GenerateGo generates the Go source of a function evaluating the
+// expression. The function is named after the expression ID by default.
+func (es *expressionService) GenerateGo(ctx context.Context, ID int64, opts logic.GoOptions) (string, error) {
+	_, node, err := es.getParsedExpression(ctx, ID)
 	if err != nil {
-		return false, fmt.Errorf("error evaluating expression %q: %w", exp.Value, err)
+		return "", err
+	}
+
+	if opts.Function == "" {
+		opts.Function = fmt.Sprintf("Rule%d", ID)
 	}
 
-	return res, nil
+	code, err := logic.ToGo(node, opts)
+	if err != nil {
+		return "", fmt.Errorf("error generating Go code for expression ID %d: %w", ID, err)
+	}
+
+	return string(code), nil
+}
+
+// getParsedExpression returns the stored expression along with its tree.
+func (es *expressionService) getParsedExpression(ctx context.Context, ID int64) (*repositories.Expression, *logic.Node, error) {
+	exp, err := es.GetExpression(ctx, ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, err := logic.Parse(exp.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing expression ID %d: %w", ID, err)
+	}
+
+	return exp, node, nil
+}
+
+// validateExpression checks the expression is a valid logical expression
+// within the limits of the service.
+func (es *expressionService) validateExpression(expression string) error {
+	if expression == "" {
+		return fmt.Errorf("value can't be empty")
+	}
+
+	if err := es.limits.check(expression); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EvaluateExpression evaluates the expression, failing when a parameter is
+// missing.
+func (es *expressionService) EvaluateExpression(ctx context.Context, ID int64, parameters map[string]int) (bool, error) {
+	res, err := es.EvaluateExpressionWithOptions(ctx, ID, parameters, EvaluationOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	return res == logic.True, nil
 }
 
 type ExpressionServiceOption func(es *expressionService)
 
 func NewExpressionService(options ...ExpressionServiceOption) ExpressionService {
-	es := &expressionService{}
+	es := &expressionService{
+		compiled:      newCompiledCache(compiledCacheNodes),
+		limits:        DefaultLimits,
+		matchIndexTTL: DefaultMatchIndexTTL,
+	}
 
 	for _, option := range options {
 		option(es)
@@ -121,3 +439,27 @@ func WithExpressionRepositoryOption(er repositories.ExpressionRepository) Expres
 		es.expressionRepository = er
 	}
 }
+
+// DefaultMatchIndexTTL is how long the index of MatchExpressions is used
+// before being reloaded, for a service created without
+// WithMatchIndexTTLOption.
+const DefaultMatchIndexTTL = time.Minute
+
+// WithMatchIndexTTLOption sets how long the index of MatchExpressions is
+// used before being reloaded from the repository. The writes of the service
+// are applied to the index right away, but the ones of other instances, or
+// made to the database directly, are only seen on reload. Zero never
+// reloads it.
+func WithMatchIndexTTLOption(ttl time.Duration) ExpressionServiceOption {
+	return func(es *expressionService) {
+		es.matchIndexTTL = ttl
+	}
+}
+
+// WithBDDEvaluationOption makes EvaluateExpression compile expressions into
+// BDDs once and evaluate them by following their decisions.
+func WithBDDEvaluationOption() ExpressionServiceOption {
+	return func(es *expressionService) {
+		es.bddEvaluation = true
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
 	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,9 +84,140 @@ func TestExpressionService_CreateExpression(t *testing.T) {
 		assert.Equal(t, expectedExp, exp)
 	})
 
+	t.Run("normalizes expressions written in other dialects", func(t *testing.T) {
+		testCases := []struct {
+			value, dialect string
+			expect         *repositories.Expression
+		}{
+			{
+				value: "x and not y",
+				expect: &repositories.Expression{
+					Value:    "x AND NOT y",
+					Original: "x and not y",
+					Dialect:  "lowercase",
+				},
+			},
+			{
+				value: "x ∧ (y ∨ ¬z)",
+				expect: &repositories.Expression{
+					Value:    "x AND (y OR NOT z)",
+					Original: "x ∧ (y ∨ ¬z)",
+					Dialect:  "unicode",
+				},
+			},
+			{
+				value:   "x && y",
+				dialect: "c",
+				expect: &repositories.Expression{
+					Value:    "x AND y",
+					Original: "x && y",
+					Dialect:  "c",
+				},
+			},
+		}
+
+		for _, tc := range testCases {
+			expressionRepositoryMock.
+				On("CreateExpression", ctx, tc.expect).
+				Return(tc.expect, nil).
+				Once()
+
+			exp, err := expressionService.CreateExpression(ctx, &repositories.Expression{
+				Value:   tc.value,
+				Dialect: tc.dialect,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, exp)
+		}
+
+		exp, err := expressionService.CreateExpression(ctx, &repositories.Expression{
+			Value:   "x && y",
+			Dialect: "lowercase",
+		})
+
+		assert.EqualError(t, err, ErrInvalidExpression.Error())
+		assert.Nil(t, exp)
+
+		exp, err = expressionService.CreateExpression(ctx, &repositories.Expression{
+			Value:   "x && y",
+			Dialect: "pascal",
+		})
+
+		assert.ErrorIs(t, err, logic.ErrUnknownDialect)
+		assert.Nil(t, exp)
+	})
+
+	t.Run("formats the expression when asked to", func(t *testing.T) {
+		expectedExp := &repositories.Expression{
+			Value:    "x AND y OR NOT z",
+			Original: "((x &&  y) || !z)",
+			Dialect:  "c",
+		}
+
+		expressionRepositoryMock.
+			On("CreateExpression", ctx, expectedExp).
+			Return(expectedExp, nil).
+			Once()
+
+		exp, err := expressionService.CreateExpression(ctx, &repositories.Expression{
+			Value: "((x &&  y) || !z)",
+		}, WithFormatOption(logic.FormatOptions{}))
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedExp, exp)
+
+		expectedExp = &repositories.Expression{
+			Value: "x AND y",
+		}
+
+		expressionRepositoryMock.
+			On("CreateExpression", ctx, expectedExp).
+			Return(expectedExp, nil).
+			Once()
+
+		exp, err = expressionService.CreateExpression(ctx, &repositories.Expression{
+			Value: "x AND y",
+		}, WithFormatOption(logic.FormatOptions{}))
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedExp, exp)
+	})
+
 	expressionRepositoryMock.AssertExpectations(t)
 }
 
+func TestExpressionService_FormatExpression(t *testing.T) {
+	expressionService := NewExpressionService()
+
+	ctx := context.Background()
+
+	t.Run("returns error when the expression is invalid", func(t *testing.T) {
+		formatted, err := expressionService.FormatExpression(ctx, &repositories.Expression{
+			Value: "(x AND",
+		}, logic.FormatOptions{})
+
+		assert.EqualError(t, err, ErrInvalidExpression.Error())
+		assert.Empty(t, formatted)
+	})
+
+	t.Run("formats expressions", func(t *testing.T) {
+		formatted, err := expressionService.FormatExpression(ctx, &repositories.Expression{
+			Value: "((x OR y)   AND (z))",
+		}, logic.FormatOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "(x OR y) AND z", formatted)
+
+		formatted, err = expressionService.FormatExpression(ctx, &repositories.Expression{
+			Value: "(first ∧ second) ∨ third",
+		}, logic.FormatOptions{Width: 20})
+		require.NoError(t, err)
+
+		assert.Equal(t, "first AND second\nOR third", formatted)
+	})
+}
+
 func TestExpressionService_ListExpressions(t *testing.T) {
 	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
 	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
@@ -138,6 +270,86 @@ func TestExpressionService_ListExpressions(t *testing.T) {
 	})
 }
 
+func TestExpressionService_GetExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		exp, err := expressionService.GetExpression(ctx, 1)
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, exp)
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		exp, err = expressionService.GetExpression(ctx, 1)
+
+		assert.EqualError(t, err, "error getting expression ID 1: unexpected error")
+		assert.Nil(t, exp)
+	})
+
+	t.Run("returns the expression correctly", func(t *testing.T) {
+		expectedExp := &repositories.Expression{
+			ID:    1,
+			Value: "x AND y",
+		}
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(expectedExp, nil).
+			Once()
+
+		exp, err := expressionService.GetExpression(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedExp, exp)
+	})
+
+	t.Run("upgrades the expressions stored in the legacy syntax", func(t *testing.T) {
+		testCases := []struct {
+			value  string
+			expect repositories.Expression
+		}{
+			{
+				value:  "x && !y",
+				expect: repositories.Expression{ID: 1, Value: "x AND NOT y", Original: "x && !y", Dialect: "c"},
+			},
+			{
+				value:  "(x AND !y) || z",
+				expect: repositories.Expression{ID: 1, Value: "(x AND NOT y) OR z", Original: "(x AND !y) || z", Dialect: "mixed"},
+			},
+			{
+				value:  "x == y",
+				expect: repositories.Expression{ID: 1, Value: "x == y"},
+			},
+		}
+
+		for _, tc := range testCases {
+			expressionRepositoryMock.
+				On("GetExpressionByID", ctx, int64(1)).
+				Return(&repositories.Expression{ID: 1, Value: tc.value}, nil).
+				Once()
+
+			exp, err := expressionService.GetExpression(ctx, 1)
+			require.NoError(t, err)
+
+			assert.Equal(t, &tc.expect, exp, tc.value)
+		}
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
 func TestExpressionService_UpdateExpression(t *testing.T) {
 	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
 	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
@@ -193,7 +405,7 @@ func TestExpressionService_UpdateExpression(t *testing.T) {
 		}
 
 		expressionRepositoryMock.
-			On("UpdateExpression", ctx, exp).
+			On("UpdateExpression", ctx, exp, false).
 			Return(nil, errors.New("unexpected error")).
 			Once()
 
@@ -208,7 +420,7 @@ func TestExpressionService_UpdateExpression(t *testing.T) {
 		}
 
 		expressionRepositoryMock.
-			On("UpdateExpression", ctx, exp).
+			On("UpdateExpression", ctx, exp, false).
 			Return(nil, repositories.ErrNoRowsAffected).
 			Once()
 
@@ -230,7 +442,7 @@ func TestExpressionService_UpdateExpression(t *testing.T) {
 		}
 
 		expressionRepositoryMock.
-			On("UpdateExpression", ctx, exp).
+			On("UpdateExpression", ctx, exp, false).
 			Return(expectedExp, nil).
 			Once()
 
@@ -243,6 +455,267 @@ func TestExpressionService_UpdateExpression(t *testing.T) {
 	expressionRepositoryMock.AssertExpectations(t)
 }
 
+func TestExpressionService_DeleteExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("DeleteExpression", ctx, int64(1)).
+			Return(repositories.ErrExpressionNotFound).
+			Once()
+
+		err := expressionService.DeleteExpression(ctx, 1)
+
+		assert.Equal(t, repositories.ErrExpressionNotFound, err)
+
+		expressionRepositoryMock.
+			On("HardDeleteExpression", ctx, int64(1)).
+			Return(errors.New("unexpected error")).
+			Once()
+
+		err = expressionService.DeleteExpression(ctx, 1, WithHardDeleteOption())
+
+		assert.EqualError(t, err, "error deleting expression ID 1: unexpected error")
+	})
+
+	t.Run("soft deletes the expression by default", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("DeleteExpression", ctx, int64(1)).
+			Return(nil).
+			Once()
+
+		err := expressionService.DeleteExpression(ctx, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("hard deletes the expression", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("HardDeleteExpression", ctx, int64(1)).
+			Return(nil).
+			Once()
+
+		err := expressionService.DeleteExpression(ctx, 1, WithHardDeleteOption())
+		require.NoError(t, err)
+	})
+
+	t.Run("stops matching deleted expressions until restored", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		exp := &repositories.Expression{ID: 1, Value: "x"}
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{*exp}, nil).Once()
+		expressionRepositoryMock.On("DeleteExpression", ctx, int64(1)).Return(nil).Once()
+		expressionRepositoryMock.On("RestoreExpression", ctx, int64(1)).Return(exp, nil).Once()
+
+		res, err := expressionService.MatchExpressions(ctx, map[string]int{"x": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+
+		err = expressionService.DeleteExpression(ctx, 1)
+		require.NoError(t, err)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, res)
+
+		_, err = expressionService.RestoreExpression(ctx, 1)
+		require.NoError(t, err)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+
+		expressionRepositoryMock.AssertExpectations(t)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_RestoreExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("RestoreExpression", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		exp, err := expressionService.RestoreExpression(ctx, 1)
+
+		assert.Equal(t, repositories.ErrExpressionNotFound, err)
+		assert.Nil(t, exp)
+
+		expressionRepositoryMock.
+			On("RestoreExpression", ctx, int64(1)).
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		exp, err = expressionService.RestoreExpression(ctx, 1)
+
+		assert.EqualError(t, err, "error restoring expression ID 1: unexpected error")
+		assert.Nil(t, exp)
+	})
+
+	t.Run("returns the restored expression", func(t *testing.T) {
+		expectedExp := &repositories.Expression{ID: 1, Value: "x AND y"}
+
+		expressionRepositoryMock.
+			On("RestoreExpression", ctx, int64(1)).
+			Return(expectedExp, nil).
+			Once()
+
+		exp, err := expressionService.RestoreExpression(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedExp, exp)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_CompileExpressionToSQL(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(nil, repositories.ErrExpressionNotFound).
+			Once()
+
+		fragment, err := expressionService.CompileExpressionToSQL(ctx, 1, logic.SQLOptions{})
+
+		assert.EqualError(t, err, repositories.ErrExpressionNotFound.Error())
+		assert.Nil(t, fragment)
+	})
+
+	t.Run("returns error when a variable isn't mapped", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		fragment, err := expressionService.CompileExpressionToSQL(ctx, 1, logic.SQLOptions{
+			Columns: map[string]string{"x": "active"},
+		})
+
+		assert.EqualError(t, err, "error compiling expression ID 1 to SQL: variable not mapped to a column: y")
+		assert.Nil(t, fragment)
+	})
+
+	t.Run("compiles the expression correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND NOT y",
+			}, nil).
+			Once()
+
+		fragment, err := expressionService.CompileExpressionToSQL(ctx, 1, logic.SQLOptions{
+			Columns: map[string]string{"x": "active", "y": "banned"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &logic.SQLFragment{
+			Where: `"active" AND NOT "banned"`,
+			Args:  []interface{}{},
+		}, fragment)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_ExportNetlist(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the format is unknown", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		netlist, err := expressionService.ExportNetlist(ctx, 1, "vhdl")
+
+		assert.ErrorIs(t, err, logic.ErrUnknownNetlistFormat)
+		assert.Empty(t, netlist)
+	})
+
+	t.Run("exports the expression correctly", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND NOT y",
+			}, nil).
+			Once()
+
+		netlist, err := expressionService.ExportNetlist(ctx, 1, logic.NetlistFormatBLIF)
+		require.NoError(t, err)
+
+		assert.Equal(t, ".model expression_1\n.inputs x y\n.outputs out\n.names x y out\n10 1\n.end\n", netlist)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_GenerateGo(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the function name is invalid", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(42)).
+			Return(&repositories.Expression{
+				ID:    42,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		code, err := expressionService.GenerateGo(ctx, 42, logic.GoOptions{Function: "my-rule"})
+
+		assert.ErrorIs(t, err, logic.ErrInvalidGoIdentifier)
+		assert.Empty(t, code)
+	})
+
+	t.Run("names the function after the expression ID", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(42)).
+			Return(&repositories.Expression{
+				ID:    42,
+				Value: "x AND y",
+			}, nil).
+			Once()
+
+		code, err := expressionService.GenerateGo(ctx, 42, logic.GoOptions{})
+		require.NoError(t, err)
+
+		assert.Contains(t, code, "func Rule42(x, y bool) bool {")
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
 func TestExpressionService_EvaluateExpression(t *testing.T) {
 	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
 	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
@@ -273,6 +746,18 @@ func TestExpressionService_EvaluateExpression(t *testing.T) {
 		assert.False(t, res)
 	})
 
+	t.Run("evaluates the expressions stored in the legacy syntax", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{ID: 1, Value: "x AND !y"}, nil).
+			Once()
+
+		res, err := expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 1, "y": 0})
+		require.NoError(t, err)
+
+		assert.True(t, res)
+	})
+
 	t.Run("returns error when a parameter is missing", func(t *testing.T) {
 		ID := int64(1)
 
@@ -373,3 +858,32 @@ func TestExpressionService_EvaluateExpression(t *testing.T) {
 
 	expressionRepositoryMock.AssertExpectations(t)
 }
+
+func TestExpressionService_EvaluateExpression_BDD(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(
+		WithExpressionRepositoryOption(expressionRepositoryMock),
+		WithBDDEvaluationOption(),
+	)
+
+	ctx := context.Background()
+
+	expressionRepositoryMock.
+		On("GetExpressionByID", ctx, int64(1)).
+		Return(&repositories.Expression{
+			ID:    1,
+			Value: "((x OR y) AND (z OR k) OR j)",
+		}, nil)
+
+	res, err := expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 1, "y": 0, "z": 1, "k": 0, "j": 0})
+	require.NoError(t, err)
+	assert.True(t, res)
+
+	res, err = expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 0, "y": 0, "z": 1, "k": 0, "j": 0})
+	require.NoError(t, err)
+	assert.False(t, res)
+
+	res, err = expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 1})
+	assert.Error(t, err)
+	assert.False(t, res)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+)
+
+var ErrInvalidSynthesis = errors.New("invalid synthesis request")
+
+// maxSynthesisVariables bounds the size of the truth tables synthesis works
+// on, the minimization being exponential in the number of variables.
+const maxSynthesisVariables = 10
+
+type SynthesisOptions struct {
+	// Variables are the inputs of the expression. Rows of the truth table
+	// are numbered with the first variable as the most significant bit.
+	// When synthesizing from examples, it defaults to the variables of the
+	// examples in alphabetical order.
+	Variables []string
+	// TruthTable holds the outcome of every row, nil marking a don't-care.
+	TruthTable []*bool
+	// Examples are labelled assignments. Variables missing from an example
+	// may take any value, and rows no example covers are don't-cares.
+	Examples []SynthesisExample
+	// Save stores the synthesized expression as a new expression.
+	Save bool
+}
+
+type SynthesisExample struct {
+	Parameters map[string]int
+	Result     bool
+}
+
+type SynthesisResult struct {
+	Expression string
+	// Saved is the created expression, when saving was requested.
+	Saved *repositories.Expression
+}
+
+// SynthesizeExpression builds a minimal sum of products consistent with a
+// truth table or a set of labelled examples.
+func (es *expressionService) SynthesizeExpression(ctx context.Context, opts SynthesisOptions) (*SynthesisResult, error) {
+	var (
+		variables           []string
+		minterms, dontCares []uint64
+		err                 error
+	)
+
+	switch {
+	case opts.TruthTable != nil && opts.Examples != nil:
+		return nil, fmt.Errorf("%w: provide either a truth table or examples", ErrInvalidSynthesis)
+	case opts.TruthTable != nil:
+		variables = opts.Variables
+		minterms, dontCares, err = truthTableRows(variables, opts.TruthTable)
+	case opts.Examples != nil:
+		variables = opts.Variables
+		if len(variables) == 0 {
+			variables = exampleVariables(opts.Examples)
+		}
+		minterms, dontCares, err = exampleRows(variables, opts.Examples)
+	default:
+		return nil, fmt.Errorf("%w: a truth table or examples are required", ErrInvalidSynthesis)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := logic.Minimize(ctx, variables, minterms, dontCares)
+	if errors.Is(err, logic.ErrTooManyImplicants) {
+		return nil, fmt.Errorf("%w: the function is too complex to minimize", ErrInvalidSynthesis)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error minimizing the expression: %w", err)
+	}
+
+	res := &SynthesisResult{Expression: node.String()}
+
+	// The expression must be one the service accepts, saved or not.
+	if err := es.limits.check(res.Expression); err != nil {
+		return nil, fmt.Errorf("%w: the synthesized expression exceeds the limits: %w", ErrInvalidSynthesis, err)
+	}
+
+	if opts.Save {
+		res.Saved, err = es.CreateExpression(ctx, &repositories.Expression{
+			Value:   res.Expression,
+			Dialect: string(logic.DialectCanonical),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func checkSynthesisVariables(variables []string) error {
+	if len(variables) > maxSynthesisVariables {
+		return fmt.Errorf("%w: at most %d variables are supported", ErrInvalidSynthesis, maxSynthesisVariables)
+	}
+
+	seen := make(map[string]struct{}, len(variables))
+	for _, name := range variables {
+		node, err := logic.Parse(name)
+		if err != nil || node.Kind != logic.KindVariable {
+			return fmt.Errorf("%w: invalid variable name %q", ErrInvalidSynthesis, name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: duplicated variable %q", ErrInvalidSynthesis, name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	return nil
+}
+
+func truthTableRows(variables []string, table []*bool) (minterms, dontCares []uint64, err error) {
+	if err := checkSynthesisVariables(variables); err != nil {
+		return nil, nil, err
+	}
+
+	if rows := 1 << len(variables); len(table) != rows {
+		return nil, nil, fmt.Errorf("%w: the truth table of %d variables must have %d rows", ErrInvalidSynthesis, len(variables), rows)
+	}
+
+	for row, result := range table {
+		switch {
+		case result == nil:
+			dontCares = append(dontCares, uint64(row))
+		case *result:
+			minterms = append(minterms, uint64(row))
+		}
+	}
+
+	return minterms, dontCares, nil
+}
+
+// exampleVariables returns the variables of the examples, sorted.
+func exampleVariables(examples []SynthesisExample) []string {
+	seen := map[string]struct{}{}
+	for _, example := range examples {
+		for name := range example.Parameters {
+			seen[name] = struct{}{}
+		}
+	}
+
+	variables := make([]string, 0, len(seen))
+	for name := range seen {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+
+	return variables
+}
+
+func exampleRows(variables []string, examples []SynthesisExample) (minterms, dontCares []uint64, err error) {
+	if err := checkSynthesisVariables(variables); err != nil {
+		return nil, nil, err
+	}
+
+	n := len(variables)
+	position := make(map[string]int, n)
+	for v, name := range variables {
+		position[name] = v
+	}
+
+	labels := make(map[uint64]bool)
+	for i, example := range examples {
+		// Variables missing from the example are free, so the example
+		// labels every row matching the assigned ones.
+		term := logic.Implicant{Mask: 1<<n - 1}
+		for name, value := range example.Parameters {
+			v, ok := position[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: example %d uses unknown variable %q", ErrInvalidSynthesis, i, name)
+			}
+			if value != 0 && value != 1 {
+				return nil, nil, fmt.Errorf("%w: example %d assigns %d to %q, expected 0 or 1", ErrInvalidSynthesis, i, value, name)
+			}
+
+			bit := uint64(1) << (n - 1 - v)
+			term.Mask &^= bit
+			if value == 1 {
+				term.Value |= bit
+			}
+		}
+
+		for row := uint64(0); row < 1<<n; row++ {
+			if !term.Covers(row) {
+				continue
+			}
+			if label, ok := labels[row]; ok && label != example.Result {
+				return nil, nil, fmt.Errorf("%w: example %d contradicts a previous example", ErrInvalidSynthesis, i)
+			}
+			labels[row] = example.Result
+		}
+	}
+
+	for row := uint64(0); row < 1<<n; row++ {
+		label, ok := labels[row]
+		switch {
+		case !ok:
+			dontCares = append(dontCares, row)
+		case label:
+			minterms = append(minterms, row)
+		}
+	}
+
+	return minterms, dontCares, nil
+}
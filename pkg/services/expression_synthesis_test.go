@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionService_SynthesizeExpression(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	yes, no := true, false
+
+	t.Run("returns error when the request is invalid", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			opts   SynthesisOptions
+			expect string
+		}{
+			{
+				name:   "nothing provided",
+				opts:   SynthesisOptions{},
+				expect: "invalid synthesis request: a truth table or examples are required",
+			},
+			{
+				name: "both provided",
+				opts: SynthesisOptions{
+					TruthTable: []*bool{&yes, &no},
+					Examples:   []SynthesisExample{{Result: true}},
+				},
+				expect: "invalid synthesis request: provide either a truth table or examples",
+			},
+			{
+				name: "truth table with the wrong size",
+				opts: SynthesisOptions{
+					Variables:  []string{"x", "y"},
+					TruthTable: []*bool{&yes, &no},
+				},
+				expect: "invalid synthesis request: the truth table of 2 variables must have 4 rows",
+			},
+			{
+				name: "invalid variable name",
+				opts: SynthesisOptions{
+					Variables:  []string{"x1"},
+					TruthTable: []*bool{&yes, &no},
+				},
+				expect: `invalid synthesis request: invalid variable name "x1"`,
+			},
+			{
+				name: "duplicated variable",
+				opts: SynthesisOptions{
+					Variables:  []string{"x", "x"},
+					TruthTable: []*bool{&yes, &no, &no, &no},
+				},
+				expect: `invalid synthesis request: duplicated variable "x"`,
+			},
+			{
+				name: "non-boolean parameter",
+				opts: SynthesisOptions{
+					Examples: []SynthesisExample{{Parameters: map[string]int{"x": 2}, Result: true}},
+				},
+				expect: `invalid synthesis request: example 0 assigns 2 to "x", expected 0 or 1`,
+			},
+			{
+				name: "unknown variable",
+				opts: SynthesisOptions{
+					Variables: []string{"x"},
+					Examples:  []SynthesisExample{{Parameters: map[string]int{"y": 1}, Result: true}},
+				},
+				expect: `invalid synthesis request: example 0 uses unknown variable "y"`,
+			},
+			{
+				name: "contradicting examples",
+				opts: SynthesisOptions{
+					Examples: []SynthesisExample{
+						{Parameters: map[string]int{"x": 1}, Result: true},
+						{Parameters: map[string]int{"x": 1, "y": 0}, Result: false},
+					},
+				},
+				expect: "invalid synthesis request: example 1 contradicts a previous example",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				res, err := expressionService.SynthesizeExpression(ctx, tc.opts)
+
+				assert.ErrorIs(t, err, ErrInvalidSynthesis)
+				assert.EqualError(t, err, tc.expect)
+				assert.Nil(t, res)
+			})
+		}
+	})
+
+	// table returns the truth table of n variables marking the even rows
+	// as don't-cares and the odd multiples of 3 as true, whose
+	// minimization builds a huge number of implicants.
+	table := func(n int) ([]string, []*bool) {
+		variables := make([]string, n)
+		rows := make([]*bool, 1<<n)
+		for i := range variables {
+			variables[i] = string(rune('a' + i))
+		}
+		for row := range rows {
+			switch {
+			case row%2 == 0:
+			case row%3 == 0:
+				rows[row] = &yes
+			default:
+				rows[row] = &no
+			}
+		}
+		return variables, rows
+	}
+
+	t.Run("returns error when the truth table is too large", func(t *testing.T) {
+		variables, rows := table(11)
+
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{Variables: variables, TruthTable: rows})
+
+		assert.EqualError(t, err, "invalid synthesis request: at most 10 variables are supported")
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns error when the function is too complex", func(t *testing.T) {
+		variables, rows := table(10)
+
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{Variables: variables, TruthTable: rows})
+
+		assert.EqualError(t, err, "invalid synthesis request: the function is too complex to minimize")
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns error when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		variables, rows := table(10)
+
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{Variables: variables, TruthTable: rows})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, res)
+	})
+
+	t.Run("returns error when the expression exceeds the limits", func(t *testing.T) {
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithLimitsOption(Limits{MaxLength: 10}),
+		)
+
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{
+			Variables:  []string{"x", "y", "z"},
+			TruthTable: []*bool{&no, &no, &no, &yes, nil, &yes, &yes, &yes},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidSynthesis)
+		assert.ErrorIs(t, err, ErrExpressionTooLong)
+		assert.Nil(t, res)
+	})
+
+	t.Run("synthesizes from a truth table", func(t *testing.T) {
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{
+			Variables:  []string{"x", "y", "z"},
+			TruthTable: []*bool{&no, &no, &no, &yes, nil, &yes, &yes, &yes},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &SynthesisResult{Expression: "x OR y AND z"}, res)
+	})
+
+	t.Run("synthesizes from examples", func(t *testing.T) {
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{
+			Examples: []SynthesisExample{
+				{Parameters: map[string]int{"y": 1, "x": 1}, Result: true},
+				{Parameters: map[string]int{"x": 0}, Result: false},
+				{Parameters: map[string]int{"x": 1, "y": 0, "z": 0}, Result: false},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &SynthesisResult{Expression: "x AND y"}, res)
+	})
+
+	t.Run("saves the synthesized expression", func(t *testing.T) {
+		exp := &repositories.Expression{Value: "NOT x"}
+
+		expressionRepositoryMock.
+			On("CreateExpression", ctx, exp).
+			Return(&repositories.Expression{ID: 1, Value: "NOT x"}, nil).
+			Once()
+
+		res, err := expressionService.SynthesizeExpression(ctx, SynthesisOptions{
+			Variables:  []string{"x"},
+			TruthTable: []*bool{&yes, &no},
+			Save:       true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &SynthesisResult{
+			Expression: "NOT x",
+			Saved:      &repositories.Expression{ID: 1, Value: "NOT x"},
+		}, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
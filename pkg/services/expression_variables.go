@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+)
+
+// ListExpressionsByVariable returns the expressions using the variable, so
+// the impact of retiring it can be assessed.
+func (es *expressionService) ListExpressionsByVariable(ctx context.Context, name string) ([]repositories.Expression, error) {
+	exps, err := es.expressionRepository.GetExpressionsByVariable(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the expressions using variable %q: %w", name, err)
+	}
+
+	for i := range exps {
+		upgradeLegacyExpression(&exps[i])
+	}
+
+	return exps, nil
+}
+
+// RenameVariable renames the variable in every expression using it, and in
+// the variables registry, atomically. It fails with logic.ErrVariableInUse
+// when an expression already uses the new name, leaving every expression
+// untouched. The soft deleted expressions are renamed as well, to be
+// consistent once restored, but stay hidden: they're neither returned nor
+// matched.
+func (es *expressionService) RenameVariable(ctx context.Context, from, to string) ([]repositories.Expression, error) {
+	if err := validateVariableName(to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: the new name is the current one", ErrInvalidVariable)
+	}
+
+	exps, err := es.expressionRepository.RenameVariable(ctx, from, to, func(exp *repositories.Expression) error {
+		upgradeLegacyExpression(exp)
+
+		value, err := logic.RenameVariable(exp.Value, logic.DialectCanonical, from, to)
+		if err != nil {
+			return err
+		}
+
+		// The original text is in the dialect of the expression, or in the
+		// canonical one when it was only formatted.
+		original := exp.Original
+		if original != "" {
+			original, err = logic.RenameVariable(exp.Original, logic.Dialect(exp.Dialect), from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		exp.Value, exp.Original = value, original
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error renaming variable %q to %q: %w", from, to, err)
+	}
+
+	renamed := make([]repositories.Expression, 0, len(exps))
+	for i := range exps {
+		if exps[i].DeletedAt != nil {
+			continue
+		}
+
+		es.indexExpression(&exps[i])
+		renamed = append(renamed, exps[i])
+	}
+
+	return renamed, nil
+}
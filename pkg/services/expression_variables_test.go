@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionService_ListExpressionsByVariable(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionsByVariable", ctx, "x").
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		_, err := expressionService.ListExpressionsByVariable(ctx, "x")
+
+		assert.EqualError(t, err, `error getting the expressions using variable "x": unexpected error`)
+	})
+
+	t.Run("returns the expressions using the variable", func(t *testing.T) {
+		exps := []repositories.Expression{{ID: 1, Value: "x AND y"}, {ID: 3, Value: "NOT x"}}
+
+		expressionRepositoryMock.
+			On("GetExpressionsByVariable", ctx, "x").
+			Return(exps, nil).
+			Once()
+
+		got, err := expressionService.ListExpressionsByVariable(ctx, "x")
+		require.NoError(t, err)
+
+		assert.Equal(t, exps, got)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_RenameVariable(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the new name is invalid", func(t *testing.T) {
+		_, err := expressionService.RenameVariable(ctx, "x", "X")
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+
+		_, err = expressionService.RenameVariable(ctx, "x", "and")
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+
+		_, err = expressionService.RenameVariable(ctx, "x", "x")
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+
+		expressionRepositoryMock.AssertNotCalled(t, "RenameVariable", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rewrites the value and the original text", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("RenameVariable", ctx, "usr", "user").
+			Return([]repositories.Expression{
+				{ID: 1, Value: "usr AND NOT admin"},
+				{ID: 2, Value: "(usr OR guest)", Original: "usr || guest", Dialect: "c"},
+			}, nil).
+			Once()
+
+		got, err := expressionService.RenameVariable(ctx, "usr", "user")
+		require.NoError(t, err)
+
+		assert.Equal(t, []repositories.Expression{
+			{ID: 1, Value: "user AND NOT admin"},
+			{ID: 2, Value: "(user OR guest)", Original: "user || guest", Dialect: "c"},
+		}, got)
+	})
+
+	t.Run("upgrades the legacy expressions to their dialect", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("RenameVariable", ctx, "usr", "user").
+			Return([]repositories.Expression{{ID: 3, Value: "usr && !admin"}}, nil).
+			Once()
+
+		got, err := expressionService.RenameVariable(ctx, "usr", "user")
+		require.NoError(t, err)
+
+		assert.Equal(t, []repositories.Expression{
+			{ID: 3, Value: "user AND NOT admin", Original: "user && !admin", Dialect: "c"},
+		}, got)
+	})
+
+	t.Run("fails when an expression already uses the new name", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("RenameVariable", ctx, "usr", "admin").
+			Return([]repositories.Expression{{ID: 1, Value: "usr AND NOT admin"}}, nil).
+			Once()
+
+		_, err := expressionService.RenameVariable(ctx, "usr", "admin")
+
+		assert.ErrorIs(t, err, logic.ErrVariableInUse)
+	})
+
+	t.Run("keeps the soft deleted expressions hidden", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetAllExpressions", ctx).
+			Return([]repositories.Expression{
+				{ID: 1, Value: "usr AND NOT admin"},
+				{ID: 2, Value: "usr"},
+			}, nil).
+			Once()
+
+		res, err := expressionService.MatchExpressions(ctx, map[string]int{"usr": 1, "admin": 0}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, res)
+
+		expressionRepositoryMock.On("DeleteExpression", ctx, int64(2)).Return(nil).Once()
+		require.NoError(t, expressionService.DeleteExpression(ctx, 2))
+
+		deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		expressionRepositoryMock.
+			On("RenameVariable", ctx, "usr", "user").
+			Return([]repositories.Expression{
+				{ID: 1, Value: "usr AND NOT admin"},
+				{ID: 2, Value: "usr", DeletedAt: &deletedAt},
+			}, nil).
+			Once()
+
+		got, err := expressionService.RenameVariable(ctx, "usr", "user")
+		require.NoError(t, err)
+		assert.Equal(t, []repositories.Expression{{ID: 1, Value: "user AND NOT admin"}}, got)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"user": 1, "admin": 0}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+)
+
+var (
+	ErrExpressionTooLong          = errors.New("expression too long")
+	ErrExpressionTooDeep          = errors.New("expression too deeply nested")
+	ErrExpressionTooManyNodes     = errors.New("expression has too many nodes")
+	ErrExpressionTooManyVariables = errors.New("expression has too many variables")
+)
+
+// Limits bounds the complexity of the expressions accepted by the service
+// and the work done evaluating them. Zero disables a limit.
+type Limits struct {
+	// MaxLength is the maximum length of the expression text, in bytes.
+	MaxLength int
+	// MaxDepth is the maximum number of nested levels of the expression tree.
+	MaxDepth int
+	// MaxNodes is the maximum number of nodes of the expression tree.
+	MaxNodes int
+	// MaxVariables is the maximum number of distinct variables.
+	MaxVariables int
+	// MaxEvaluationSteps is the maximum number of nodes a single evaluation
+	// visits.
+	MaxEvaluationSteps int
+	// MaxBatchSize is the maximum number of parameter sets of a batch
+	// evaluation.
+	MaxBatchSize int
+	// MaxDiagramNodes is the maximum number of nodes built compiling an
+	// expression into a BDD, or running an analysis on it.
+	MaxDiagramNodes int
+}
+
+// DefaultLimits are the limits of a service created without WithLimitsOption.
+var DefaultLimits = Limits{
+	MaxLength:          10000,
+	MaxDepth:           64,
+	MaxNodes:           2000,
+	MaxVariables:       256,
+	MaxEvaluationSteps: 100000,
+	MaxBatchSize:       100000,
+	MaxDiagramNodes:    250000,
+}
+
+// WithLimitsOption replaces the default limits of the service.
+func WithLimitsOption(limits Limits) ExpressionServiceOption {
+	return func(es *expressionService) {
+		es.limits = limits
+	}
+}
+
+// Limits returns the limits of the service, so callers can bound their
+// inputs before handing them over.
+func (es *expressionService) Limits() Limits {
+	return es.limits
+}
+
+// check returns a distinct error for each limit the expression exceeds,
+// checking the length first so oversized texts are never parsed.
+func (l Limits) check(expression string) error {
+	if l.MaxLength > 0 && len(expression) > l.MaxLength {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrExpressionTooLong, len(expression), l.MaxLength)
+	}
+
+	// The nesting is bounded while parsing, so deep texts can't exhaust
+	// the stack whatever their length.
+	node, err := logic.ParseWithOptions(expression, logic.ParseOptions{
+		Dialect:  logic.DialectCanonical,
+		MaxDepth: l.MaxDepth,
+	})
+	if errors.Is(err, logic.ErrTooDeep) {
+		return fmt.Errorf("%w: the limit is %d levels", ErrExpressionTooDeep, l.MaxDepth)
+	}
+	if err != nil {
+		return ErrInvalidExpression
+	}
+
+	if depth := node.Depth(); l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("%w: %d levels, the limit is %d", ErrExpressionTooDeep, depth, l.MaxDepth)
+	}
+
+	if size := node.Size(); l.MaxNodes > 0 && size > l.MaxNodes {
+		return fmt.Errorf("%w: %d nodes, the limit is %d", ErrExpressionTooManyNodes, size, l.MaxNodes)
+	}
+
+	if variables := len(node.Variables()); l.MaxVariables > 0 && variables > l.MaxVariables {
+		return fmt.Errorf("%w: %d variables, the limit is %d", ErrExpressionTooManyVariables, variables, l.MaxVariables)
+	}
+
+	return nil
+}
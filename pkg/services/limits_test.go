@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits_check(t *testing.T) {
+	limits := Limits{MaxLength: 40, MaxDepth: 3, MaxNodes: 6, MaxVariables: 3}
+
+	testCases := []struct {
+		name       string
+		expression string
+		err        error
+		message    string
+	}{
+		{
+			name:       "within the limits",
+			expression: "x AND (y OR z)",
+		},
+		{
+			name:       "too long",
+			expression: strings.Repeat("x AND ", 10) + "x",
+			err:        ErrExpressionTooLong,
+			message:    "expression too long: 61 bytes, the limit is 40",
+		},
+		{
+			name:       "too deep",
+			expression: "x AND (y OR NOT z)",
+			err:        ErrExpressionTooDeep,
+			message:    "expression too deeply nested: 4 levels, the limit is 3",
+		},
+		{
+			name:       "too many nodes",
+			expression: "x AND y AND z AND x AND y AND z",
+			err:        ErrExpressionTooManyNodes,
+			message:    "expression has too many nodes: 7 nodes, the limit is 6",
+		},
+		{
+			name:       "too many variables",
+			expression: "w OR x OR y OR z",
+			err:        ErrExpressionTooManyVariables,
+			message:    "expression has too many variables: 4 variables, the limit is 3",
+		},
+		{
+			name:       "invalid",
+			expression: "x AND",
+			err:        ErrInvalidExpression,
+			message:    ErrInvalidExpression.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := limits.check(tc.expression)
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.err)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+
+	t.Run("bounds the nesting while parsing", func(t *testing.T) {
+		expression := strings.Repeat("(NOT ", 100000) + "x" + strings.Repeat(")", 100000)
+
+		err := Limits{MaxDepth: 64}.check(expression)
+
+		assert.ErrorIs(t, err, ErrExpressionTooDeep)
+		assert.EqualError(t, err, "expression too deeply nested: the limit is 64 levels")
+	})
+
+	t.Run("zero disables the limits", func(t *testing.T) {
+		assert.NoError(t, Limits{}.check(strings.Repeat("(", 100)+"x"+strings.Repeat(")", 100)))
+	})
+}
+
+func TestExpressionService_Limits(t *testing.T) {
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(
+		WithExpressionRepositoryOption(expressionRepositoryMock),
+		WithLimitsOption(Limits{MaxVariables: 2, MaxEvaluationSteps: 4}),
+	)
+
+	ctx := context.Background()
+
+	t.Run("rejects expressions exceeding the limits on create and update", func(t *testing.T) {
+		exp, err := expressionService.CreateExpression(ctx, &repositories.Expression{Value: "x AND y AND z"})
+		assert.ErrorIs(t, err, ErrExpressionTooManyVariables)
+		assert.Nil(t, exp)
+
+		exp, err = expressionService.UpdateExpression(ctx, &repositories.Expression{ID: 1, Value: "x AND y AND z"})
+		assert.ErrorIs(t, err, ErrExpressionTooManyVariables)
+		assert.Nil(t, exp)
+	})
+
+	t.Run("returns error when the evaluation budget is exceeded", func(t *testing.T) {
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x AND (y OR NOT x)",
+			}, nil).
+			Twice()
+
+		res, err := expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 0, "y": 0})
+		require.NoError(t, err)
+		assert.False(t, res)
+
+		res, err = expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 1, "y": 0})
+		assert.ErrorIs(t, err, logic.ErrBudgetExceeded)
+		assert.False(t, res)
+	})
+
+	t.Run("returns error when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		expressionRepositoryMock.
+			On("GetExpressionByID", ctx, int64(1)).
+			Return(&repositories.Expression{
+				ID:    1,
+				Value: "x",
+			}, nil).
+			Once()
+
+		res, err := expressionService.EvaluateExpression(ctx, 1, map[string]int{"x": 1})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, res)
+	})
+
+	expressionRepositoryMock.AssertExpectations(t)
+}
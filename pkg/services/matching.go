@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+)
+
+// MatchOptions configures MatchExpressions.
+type MatchOptions struct {
+	EvaluationOptions
+	// Namespace restricts the matching to the expressions of the namespace.
+	// Every expression is matched when it's empty.
+	Namespace string
+}
+
+// MatchExpressions returns the IDs of the expressions evaluating to true
+// with the parameters, sorted. The expressions missing parameters under
+// MissingParametersFail, or evaluating to unknown, don't match. The
+// parameters being shared by every expression, the evaluation is never
+// strict, but the parameters of registered variables must be allowed by
+// their schema.
+//
+// Only the expressions the index of the service can't rule out are
+// evaluated, every one of them under MissingParametersDefault, where the
+// absent variables may be true.
+func (es *expressionService) MatchExpressions(ctx context.Context, parameters map[string]int, opts MatchOptions) ([]int64, error) {
+	policy, err := LookupMissingParameterPolicy(string(opts.MissingParameters))
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := es.loadMatchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	truthy := make([]string, 0, len(parameters))
+	for name, value := range parameters {
+		if value > 0 {
+			truthy = append(truthy, name)
+		}
+	}
+
+	candidates := idx.candidates(truthy, opts.Namespace, policy == MissingParametersDefault)
+
+	schemas, err := es.matchingSchemas(ctx, candidates, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkParameters(schemas, parameters); err != nil {
+		return nil, err
+	}
+
+	opts.Strict = false
+
+	matches := []int64{}
+	for _, candidate := range candidates {
+		e := candidate.evaluator.withOptions(schemas, policy, opts.EvaluationOptions)
+
+		res, err := e.evaluate(ctx, parameters)
+		var missingErr *MissingParameterError
+		if errors.As(err, &missingErr) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error matching expression ID %d: %w", e.exp.ID, err)
+		}
+
+		if res == logic.True {
+			matches = append(matches, e.exp.ID)
+		}
+	}
+
+	return matches, nil
+}
+
+// matchingSchemas returns the schemas of the registered variables of the
+// candidates and of the parameters, by name, in a single lookup. It's empty
+// without a variable repository.
+func (es *expressionService) matchingSchemas(ctx context.Context, candidates []*matchEntry, parameters map[string]int) (map[string]repositories.Variable, error) {
+	if es.variableRepository == nil {
+		return nil, nil
+	}
+
+	set := utils.LogicalExpressionParametersSet{}
+	for _, candidate := range candidates {
+		for name := range candidate.evaluator.variables {
+			set[name] = struct{}{}
+		}
+	}
+	for name := range parameters {
+		set[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+
+	schemas, err := es.variableSchemas(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the variables of the expressions: %w", err)
+	}
+
+	return schemas, nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+)
+
+// matchIndex is an inverted index of the stored expressions for
+// MatchExpressions. Every expression is indexed on one of its required
+// variables, the ones which must be true for it to be true, so only the
+// expressions indexed on a true parameter, or having no required variable,
+// can match. It's loaded from the repository on first use, and then kept up
+// to date by the writes of the service, and reloaded once older than the TTL
+// of the service to see the other writes. The zero value is an unloaded
+// index.
+type matchIndex struct {
+	mu       sync.RWMutex
+	loaded   bool
+	loadedAt time.Time
+
+	entries map[int64]*matchEntry
+	// postings holds the entries by the variable they're indexed on.
+	postings map[string]map[int64]*matchEntry
+	// unindexed holds the entries without required variables.
+	unindexed map[int64]*matchEntry
+}
+
+// matchEntry is an indexed expression, parsed and compiled once.
+type matchEntry struct {
+	evaluator *evaluator
+	// key is the variable the entry is indexed on, empty when it has no
+	// required variable.
+	key string
+}
+
+// loadMatchIndex returns the index of the expressions, loading it on first
+// use and reloading it once expired. The expressions which can't be indexed
+// are logged and skipped, so they never match.
+func (es *expressionService) loadMatchIndex(ctx context.Context) (*matchIndex, error) {
+	idx := &es.matching
+
+	idx.mu.RLock()
+	fresh := es.freshMatchIndex()
+	idx.mu.RUnlock()
+	if fresh {
+		return idx, nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if es.freshMatchIndex() {
+		return idx, nil
+	}
+
+	exps, err := es.expressionRepository.GetAllExpressions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting expressions: %w", err)
+	}
+
+	idx.entries = make(map[int64]*matchEntry, len(exps))
+	idx.postings = map[string]map[int64]*matchEntry{}
+	idx.unindexed = map[int64]*matchEntry{}
+	for i := range exps {
+		upgradeLegacyExpression(&exps[i])
+		if err := es.putMatchEntry(&exps[i]); err != nil {
+			log.Printf("error indexing expression ID %d for matching: %s", exps[i].ID, err.Error())
+		}
+	}
+	idx.loaded, idx.loadedAt = true, time.Now()
+
+	return idx, nil
+}
+
+// freshMatchIndex reports whether the index is loaded and not expired. The
+// lock must be held.
+func (es *expressionService) freshMatchIndex() bool {
+	idx := &es.matching
+	return idx.loaded && (es.matchIndexTTL <= 0 || time.Since(idx.loadedAt) < es.matchIndexTTL)
+}
+
+// indexExpression adds or replaces the expression in the index, if it's
+// loaded. It's called once the expression is saved, so an expression which
+// can't be indexed is logged and left out of the index rather than failing
+// the write.
+func (es *expressionService) indexExpression(exp *repositories.Expression) {
+	es.matching.mu.Lock()
+	defer es.matching.mu.Unlock()
+
+	if !es.matching.loaded {
+		return
+	}
+
+	indexed := *exp
+	if err := es.putMatchEntry(&indexed); err != nil {
+		log.Printf("error indexing expression ID %d for matching: %s", exp.ID, err.Error())
+	}
+}
+
+// unindexExpression removes the expression from the index, if it's loaded.
+func (es *expressionService) unindexExpression(ID int64) {
+	es.matching.mu.Lock()
+	defer es.matching.mu.Unlock()
+
+	if es.matching.loaded {
+		es.matching.remove(ID)
+	}
+}
+
+// putMatchEntry adds or replaces the expression in the index, indexing it on
+// its least used required variable. It's removed from the index when it
+// can't be parsed. The lock must be held.
+func (es *expressionService) putMatchEntry(exp *repositories.Expression) error {
+	idx := &es.matching
+
+	idx.remove(exp.ID)
+
+	// The index outlives the requests, so the expression is compiled
+	// whatever their context, within the node limit.
+	e, err := es.newEvaluator(context.Background(), exp)
+	if err != nil {
+		return err
+	}
+
+	entry := &matchEntry{evaluator: e}
+	for _, name := range e.node.RequiredVariables() {
+		if entry.key == "" || len(idx.postings[name]) < len(idx.postings[entry.key]) {
+			entry.key = name
+		}
+	}
+
+	idx.entries[exp.ID] = entry
+	if entry.key == "" {
+		idx.unindexed[exp.ID] = entry
+		return nil
+	}
+
+	if idx.postings[entry.key] == nil {
+		idx.postings[entry.key] = map[int64]*matchEntry{}
+	}
+	idx.postings[entry.key][exp.ID] = entry
+
+	return nil
+}
+
+// remove removes the expression from the index. The lock must be held.
+func (idx *matchIndex) remove(ID int64) {
+	entry, ok := idx.entries[ID]
+	if !ok {
+		return
+	}
+
+	delete(idx.entries, ID)
+	if entry.key == "" {
+		delete(idx.unindexed, ID)
+		return
+	}
+
+	delete(idx.postings[entry.key], ID)
+	if len(idx.postings[entry.key]) == 0 {
+		delete(idx.postings, entry.key)
+	}
+}
+
+// candidates returns the entries of the namespace which can be true with
+// the given true variables, sorted by ID. Every entry of the namespace is
+// returned when all is set.
+func (idx *matchIndex) candidates(truthy []string, namespace string, all bool) []*matchEntry {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var entries []*matchEntry
+	add := func(postings map[int64]*matchEntry) {
+		for _, entry := range postings {
+			if namespace == "" || entry.evaluator.exp.Namespace == namespace {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if all {
+		add(idx.entries)
+	} else {
+		add(idx.unindexed)
+		for _, name := range truthy {
+			add(idx.postings[name])
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].evaluator.exp.ID < entries[j].evaluator.exp.ID
+	})
+
+	return entries
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionService_MatchExpressionsIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("only evaluates the expressions which can match", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock)).(*expressionService)
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{
+			{ID: 1, Value: "x AND y"},
+			{ID: 2, Value: "x AND z"},
+			{ID: 3, Value: "(w AND y) OR (w AND z)"},
+			{ID: 4, Value: "NOT x OR y"},
+		}, nil).Once()
+
+		idx, err := expressionService.loadMatchIndex(ctx)
+		require.NoError(t, err)
+
+		ids := func(entries []*matchEntry) []int64 {
+			res := []int64{}
+			for _, entry := range entries {
+				res = append(res, entry.evaluator.exp.ID)
+			}
+			return res
+		}
+
+		// x AND z is indexed on z, x being already used by x AND y.
+		assert.Equal(t, []int64{1, 4}, ids(idx.candidates([]string{"x"}, "", false)))
+		assert.Equal(t, []int64{2, 4}, ids(idx.candidates([]string{"z"}, "", false)))
+		assert.Equal(t, []int64{3, 4}, ids(idx.candidates([]string{"w"}, "", false)))
+		assert.Equal(t, []int64{4}, ids(idx.candidates(nil, "", false)))
+		assert.Equal(t, []int64{1, 2, 3, 4}, ids(idx.candidates(nil, "", true)))
+	})
+
+	t.Run("keeps the index up to date", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{
+			{ID: 1, Value: "x AND y"},
+		}, nil).Once()
+
+		res, err := expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1, "z": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+
+		expressionRepositoryMock.
+			On("CreateExpression", ctx, mock.Anything).
+			Return(&repositories.Expression{ID: 2, Value: "z"}, nil).
+			Once()
+		_, err = expressionService.CreateExpression(ctx, &repositories.Expression{Value: "z"})
+		require.NoError(t, err)
+
+		expressionRepositoryMock.
+			On("UpdateExpression", ctx, mock.Anything, false).
+			Return(&repositories.Expression{ID: 1, Value: "x AND NOT y"}, nil).
+			Once()
+		_, err = expressionService.UpdateExpression(ctx, &repositories.Expression{ID: 1, Value: "x AND NOT y"})
+		require.NoError(t, err)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1, "z": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, res)
+
+		expressionRepositoryMock.
+			On("RenameVariable", ctx, "z", "w").
+			Return([]repositories.Expression{{ID: 2, Value: "z"}}, nil).
+			Once()
+		_, err = expressionService.RenameVariable(ctx, "z", "w")
+		require.NoError(t, err)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 0, "w": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, res)
+
+		expressionRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("skips the expressions which can't be indexed", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{
+			{ID: 1, Value: "x AND y"},
+			{ID: 2, Value: "x == y"},
+			{ID: 3, Value: "x && !z"},
+		}, nil).Once()
+
+		res, err := expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1, "z": 0}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3}, res)
+
+		// A saved expression which can't be indexed leaves the index
+		// without failing the write.
+		expressionRepositoryMock.
+			On("UpdateExpression", ctx, mock.Anything, false).
+			Return(&repositories.Expression{ID: 1, Value: "x =="}, nil).
+			Once()
+		_, err = expressionService.UpdateExpression(ctx, &repositories.Expression{ID: 1, Value: "x"})
+		require.NoError(t, err)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1, "z": 0}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, res)
+
+		expressionRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("reloads the index once expired", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithMatchIndexTTLOption(time.Millisecond),
+		)
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{
+			{ID: 1, Value: "x"},
+		}, nil).Once()
+
+		res, err := expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, res)
+
+		// Another instance created an expression meanwhile.
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return([]repositories.Expression{
+			{ID: 1, Value: "x"},
+			{ID: 2, Value: "y"},
+		}, nil).Once()
+
+		time.Sleep(2 * time.Millisecond)
+
+		res, err = expressionService.MatchExpressions(ctx, map[string]int{"x": 1, "y": 1}, MatchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, res)
+
+		expressionRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("matches the same expressions as a full scan", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		variables := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+		exps := make([]repositories.Expression, 500)
+		nodes := make([]*logic.Node, len(exps))
+		for i := range exps {
+			exps[i] = repositories.Expression{ID: int64(i + 1), Value: randomExpression(r, 3, variables)}
+
+			var err error
+			nodes[i], err = logic.Parse(exps[i].Value)
+			require.NoError(t, err)
+		}
+
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return(exps, nil).Once()
+
+		for n := 0; n < 50; n++ {
+			parameters := map[string]int{}
+			values := map[string]bool{}
+			for _, name := range variables {
+				parameters[name] = r.Intn(2)
+				values[name] = parameters[name] == 1
+			}
+
+			expect := []int64{}
+			for i, node := range nodes {
+				if node.Eval(values) {
+					expect = append(expect, exps[i].ID)
+				}
+			}
+
+			res, err := expressionService.MatchExpressions(ctx, parameters, MatchOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, expect, res, fmt.Sprintf("parameters %v", parameters))
+		}
+	})
+}
+
+// randomExpression returns a random expression of the variables, at most
+// depth operators deep.
+func randomExpression(r *rand.Rand, depth int, variables []string) string {
+	if depth == 0 || r.Intn(4) == 0 {
+		name := variables[r.Intn(len(variables))]
+		if r.Intn(3) == 0 {
+			return "NOT " + name
+		}
+		return name
+	}
+
+	left := randomExpression(r, depth-1, variables)
+	right := randomExpression(r, depth-1, variables)
+	if r.Intn(2) == 0 {
+		return fmt.Sprintf("(%s AND %s)", left, right)
+	}
+	return fmt.Sprintf("(%s OR %s)", left, right)
+}
+
+// benchmarkMatching matches random parameter sets against n random
+// expressions, mostly conjunctions of a few of 200 variables, as rules are.
+func benchmarkMatching(b *testing.B, n int, scan bool) {
+	ctx := context.Background()
+	r := rand.New(rand.NewSource(1))
+
+	variables := make([]string, 200)
+	for i := range variables {
+		variables[i] = fmt.Sprintf("v%c%c", 'a'+i/26, 'a'+i%26)
+	}
+
+	exps := make([]repositories.Expression, n)
+	for i := range exps {
+		value := variables[r.Intn(len(variables))]
+		for j := r.Intn(3); j >= 0; j-- {
+			value = fmt.Sprintf("%s AND (%s)", value, randomExpression(r, 2, variables))
+		}
+		exps[i] = repositories.Expression{ID: int64(i + 1), Value: value}
+	}
+
+	expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+	expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+	expressionRepositoryMock.On("GetAllExpressions", ctx).Return(exps, nil).Once()
+
+	parameters := make([]map[string]int, 64)
+	for i := range parameters {
+		parameters[i] = map[string]int{}
+		for _, name := range variables {
+			if r.Intn(10) == 0 {
+				parameters[i][name] = 1
+			} else {
+				parameters[i][name] = 0
+			}
+		}
+	}
+
+	// The default policy disables the pruning, every expression being
+	// evaluated as a plain loop would.
+	opts := MatchOptions{}
+	if scan {
+		opts.MissingParameters = MissingParametersDefault
+	}
+
+	if _, err := expressionService.MatchExpressions(ctx, parameters[0], opts); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := expressionService.MatchExpressions(ctx, parameters[i%len(parameters)], opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMatchExpressions(b *testing.B) {
+	for _, n := range []int{10000, 50000} {
+		b.Run(fmt.Sprintf("indexed/%d", n), func(b *testing.B) {
+			benchmarkMatching(b, n, false)
+		})
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			benchmarkMatching(b, n, true)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionService_MatchExpressions(t *testing.T) {
+	ctx := context.Background()
+
+	exps := []repositories.Expression{
+		{ID: 3, Value: "x AND y", Namespace: "billing"},
+		{ID: 1, Value: "x OR z"},
+		{ID: 2, Value: "NOT x"},
+		{ID: 4, Value: "y AND w", Namespace: "billing"},
+	}
+
+	testCases := []struct {
+		name       string
+		parameters map[string]int
+		opts       MatchOptions
+		expect     []int64
+	}{
+		{
+			name:       "returns the matching expressions sorted",
+			parameters: map[string]int{"x": 1, "y": 1, "z": 0},
+			expect:     []int64{1, 3},
+		},
+		{
+			name:       "skips the expressions missing parameters",
+			parameters: map[string]int{"x": 0},
+			expect:     []int64{2},
+		},
+		{
+			name:       "applies the missing parameter policy",
+			parameters: map[string]int{"x": 0, "y": 1},
+			opts:       MatchOptions{EvaluationOptions: EvaluationOptions{MissingParameters: MissingParametersDefault, Default: 1}},
+			expect:     []int64{1, 2, 4},
+		},
+		{
+			name:       "skips the expressions evaluating to unknown",
+			parameters: map[string]int{"x": 1},
+			opts:       MatchOptions{EvaluationOptions: EvaluationOptions{MissingParameters: MissingParametersUnknown}},
+			expect:     []int64{1},
+		},
+		{
+			name:       "ignores strictness",
+			parameters: map[string]int{"x": 1, "y": 1, "z": 1, "w": 1},
+			opts:       MatchOptions{EvaluationOptions: EvaluationOptions{Strict: true}},
+			expect:     []int64{1, 3, 4},
+		},
+		{
+			name:       "restricts the matching to the namespace",
+			parameters: map[string]int{"x": 1, "y": 1, "z": 1, "w": 1},
+			opts:       MatchOptions{Namespace: "billing"},
+			expect:     []int64{3, 4},
+		},
+		{
+			name:       "returns no match",
+			parameters: map[string]int{"x": 1, "y": 0, "z": 0},
+			opts:       MatchOptions{Namespace: "billing"},
+			expect:     []int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+			expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+			all := append([]repositories.Expression(nil), exps...)
+			expressionRepositoryMock.On("GetAllExpressions", ctx).Return(all, nil).Once()
+
+			res, err := expressionService.MatchExpressions(ctx, tc.parameters, tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expect, res)
+			expressionRepositoryMock.AssertExpectations(t)
+		})
+	}
+
+	t.Run("validates the parameters against the registered variables", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		variableRepositoryMock := &repositories.VariableRepositoryMock{}
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithVariableRegistryOption(variableRepositoryMock),
+		)
+
+		expressionRepositoryMock.
+			On("GetAllExpressions", ctx).
+			Return([]repositories.Expression{{ID: 1, Value: "x AND y"}, {ID: 2, Value: "y OR z"}}, nil).
+			Once()
+		variableRepositoryMock.
+			On("GetVariablesByName", ctx, []string{"age", "y", "z"}).
+			Return([]repositories.Variable{{Name: "age", Type: VariableTypeBoolean}}, nil).
+			Once()
+
+		_, err := expressionService.MatchExpressions(ctx, map[string]int{"age": 2}, MatchOptions{})
+
+		var invalidErr *InvalidParameterError
+		assert.ErrorAs(t, err, &invalidErr)
+		variableRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("fails with an unknown missing parameter policy", func(t *testing.T) {
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(&repositories.ExpressionRepositoryMock{}))
+
+		_, err := expressionService.MatchExpressions(ctx, map[string]int{}, MatchOptions{
+			EvaluationOptions: EvaluationOptions{MissingParameters: "maybe"},
+		})
+
+		assert.ErrorIs(t, err, ErrUnknownMissingParameterPolicy)
+	})
+
+	t.Run("fails when the expressions can't be listed", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(expressionRepositoryMock))
+
+		expressionRepositoryMock.On("GetAllExpressions", ctx).Return(nil, errors.New("connection lost")).Once()
+
+		_, err := expressionService.MatchExpressions(ctx, map[string]int{}, MatchOptions{})
+
+		assert.EqualError(t, err, "error getting expressions: connection lost")
+	})
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/CaioTeixeira95/logic-exp/pkg/utils"
+)
+
+var ErrInvalidVariable = errors.New("invalid variable")
+
+// The types of the registered variables. Every type is evaluated by
+// truthiness, positive values being true.
+const (
+	// VariableTypeBoolean only allows 0 and 1.
+	VariableTypeBoolean = "boolean"
+	// VariableTypeInteger allows the integers between the optional Min and
+	// Max of the variable.
+	VariableTypeInteger = "integer"
+	// VariableTypeEnum allows the values listed in the Enum of the variable.
+	VariableTypeEnum = "enum"
+)
+
+// variableNameRegex matches the names the expressions can use as variables.
+var variableNameRegex = regexp.MustCompile(`^[a-z]+$`)
+
+type VariableService interface {
+	CreateVariable(ctx context.Context, v *repositories.Variable) (*repositories.Variable, error)
+	ListVariables(ctx context.Context) ([]repositories.Variable, error)
+	GetVariable(ctx context.Context, name string) (*repositories.Variable, error)
+	UpdateVariable(ctx context.Context, v *repositories.Variable) (*repositories.Variable, error)
+	DeleteVariable(ctx context.Context, name string) error
+}
+
+type variableService struct {
+	variableRepository repositories.VariableRepository
+}
+
+func (vs *variableService) CreateVariable(ctx context.Context, v *repositories.Variable) (*repositories.Variable, error) {
+	if err := validateVariable(v); err != nil {
+		return nil, err
+	}
+
+	v, err := vs.variableRepository.CreateVariable(ctx, v)
+	if err == repositories.ErrVariableAlreadyExists {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating variable: %w", err)
+	}
+
+	return v, nil
+}
+
+func (vs *variableService) ListVariables(ctx context.Context) ([]repositories.Variable, error) {
+	vars, err := vs.variableRepository.GetAllVariables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all variables: %w", err)
+	}
+
+	return vars, nil
+}
+
+func (vs *variableService) GetVariable(ctx context.Context, name string) (*repositories.Variable, error) {
+	v, err := vs.variableRepository.GetVariableByName(ctx, name)
+	if err == repositories.ErrVariableNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting variable %q: %w", name, err)
+	}
+
+	return v, nil
+}
+
+func (vs *variableService) UpdateVariable(ctx context.Context, v *repositories.Variable) (*repositories.Variable, error) {
+	if err := validateVariable(v); err != nil {
+		return nil, err
+	}
+
+	v, err := vs.variableRepository.UpdateVariable(ctx, v)
+	if err == repositories.ErrVariableNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating variable: %w", err)
+	}
+
+	return v, nil
+}
+
+func (vs *variableService) DeleteVariable(ctx context.Context, name string) error {
+	err := vs.variableRepository.DeleteVariable(ctx, name)
+	if err == repositories.ErrVariableNotFound {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting variable %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// validateVariable checks the schema is consistent: the bounds only apply
+// to integers, the values only to enums, and the default is allowed.
+func validateVariable(v *repositories.Variable) error {
+	if err := validateVariableName(v.Name); err != nil {
+		return err
+	}
+
+	switch v.Type {
+	case VariableTypeBoolean:
+		if v.Min != nil || v.Max != nil || len(v.Enum) > 0 {
+			return fmt.Errorf("%w: boolean variables can't have a range or values", ErrInvalidVariable)
+		}
+	case VariableTypeInteger:
+		if len(v.Enum) > 0 {
+			return fmt.Errorf("%w: integer variables can't have values", ErrInvalidVariable)
+		}
+		if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+			return fmt.Errorf("%w: min %d is greater than max %d", ErrInvalidVariable, *v.Min, *v.Max)
+		}
+	case VariableTypeEnum:
+		if v.Min != nil || v.Max != nil {
+			return fmt.Errorf("%w: enum variables can't have a range", ErrInvalidVariable)
+		}
+		if len(v.Enum) == 0 {
+			return fmt.Errorf("%w: enum variables need at least one value", ErrInvalidVariable)
+		}
+
+		seen := make(map[int]struct{}, len(v.Enum))
+		for _, value := range v.Enum {
+			if _, ok := seen[value]; ok {
+				return fmt.Errorf("%w: value %d is repeated", ErrInvalidVariable, value)
+			}
+			seen[value] = struct{}{}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidVariable, v.Type)
+	}
+
+	if v.Default != nil {
+		if reason := checkVariableValue(v, *v.Default); reason != "" {
+			return fmt.Errorf("%w: default %d %s", ErrInvalidVariable, *v.Default, reason)
+		}
+	}
+
+	return nil
+}
+
+// validateVariableName checks the name can be used as a variable by the
+// expressions.
+func validateVariableName(name string) error {
+	if !variableNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: name %q must be made of lowercase letters only", ErrInvalidVariable, name)
+	}
+	if logic.IsKeyword(name) {
+		return fmt.Errorf("%w: name %q is a keyword", ErrInvalidVariable, name)
+	}
+
+	return nil
+}
+
+// checkVariableValue returns why the value isn't allowed by the schema of
+// the variable, or an empty string when it's allowed.
+func checkVariableValue(v *repositories.Variable, value int) string {
+	switch v.Type {
+	case VariableTypeBoolean:
+		if value != 0 && value != 1 {
+			return "is not a boolean, expected 0 or 1"
+		}
+	case VariableTypeInteger:
+		if v.Min != nil && value < *v.Min {
+			return fmt.Sprintf("is less than the minimum %d", *v.Min)
+		}
+		if v.Max != nil && value > *v.Max {
+			return fmt.Sprintf("is greater than the maximum %d", *v.Max)
+		}
+	case VariableTypeEnum:
+		for _, allowed := range v.Enum {
+			if value == allowed {
+				return ""
+			}
+		}
+
+		values := make([]string, 0, len(v.Enum))
+		for _, allowed := range v.Enum {
+			values = append(values, fmt.Sprint(allowed))
+		}
+		return fmt.Sprintf("is not one of %s", strings.Join(values, ", "))
+	}
+
+	return ""
+}
+
+// UnregisteredVariableError is returned when an expression required to only
+// use registered variables uses some that aren't, listed in Variables.
+type UnregisteredVariableError struct {
+	Expression string
+	Variables  []string
+}
+
+func (e *UnregisteredVariableError) Error() string {
+	quoted := make([]string, 0, len(e.Variables))
+	for _, name := range e.Variables {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+
+	noun := "variable"
+	if len(e.Variables) > 1 {
+		noun = "variables"
+	}
+	return fmt.Sprintf("unregistered %s %s in the logical expression %q", noun, strings.Join(quoted, ", "), e.Expression)
+}
+
+// InvalidParameterError is returned when an evaluation parameter isn't
+// allowed by the schema of its registered variable.
+type InvalidParameterError struct {
+	Parameter string
+	Value     int
+	Reason    string
+}
+
+func (e *InvalidParameterError) Error() string {
+	return fmt.Sprintf("invalid value %d for the parameter %q: %s", e.Value, e.Parameter, e.Reason)
+}
+
+// WithVariableRegistryOption makes the evaluations validate their
+// parameters against the registered variables, and enables
+// WithRegisteredVariablesOption.
+func WithVariableRegistryOption(vr repositories.VariableRepository) ExpressionServiceOption {
+	return func(es *expressionService) {
+		es.variableRepository = vr
+	}
+}
+
+// WithRegisteredVariablesOption makes CreateExpression reject expressions
+// using unregistered variables, which are all of them without
+// WithVariableRegistryOption. The variables are only tested for truthiness,
+// the expressions having no literals to compare them with.
+func WithRegisteredVariablesOption() CreateExpressionOption {
+	return func(o *createExpressionOptions) {
+		o.registeredVariables = true
+	}
+}
+
+// registeredVariables returns the schemas of the registered variables of
+// the expression by name. It's empty without a variable repository.
+func (es *expressionService) registeredVariables(ctx context.Context, expression string) (map[string]repositories.Variable, error) {
+	if es.variableRepository == nil {
+		return nil, nil
+	}
+
+	names := make([]string, 0)
+	for name := range utils.GetLogicalExpressionParameters(expression) {
+		names = append(names, name)
+	}
+
+	schemas, err := es.variableSchemas(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the variables of the logical expression %q: %w", expression, err)
+	}
+
+	return schemas, nil
+}
+
+// variableSchemas returns the schemas of the registered variables among the
+// names, by name.
+func (es *expressionService) variableSchemas(ctx context.Context, names []string) (map[string]repositories.Variable, error) {
+	sort.Strings(names)
+
+	vars, err := es.variableRepository.GetVariablesByName(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]repositories.Variable, len(vars))
+	for _, v := range vars {
+		schemas[v.Name] = v
+	}
+
+	return schemas, nil
+}
+
+// checkRegisteredVariables fails with an UnregisteredVariableError when the
+// expression uses variables missing from the registry. Without a registry
+// none of them are registered.
+func (es *expressionService) checkRegisteredVariables(ctx context.Context, expression string) error {
+	schemas, err := es.registeredVariables(ctx, expression)
+	if err != nil {
+		return err
+	}
+
+	var unregistered []string
+	for name := range utils.GetLogicalExpressionParameters(expression) {
+		if _, ok := schemas[name]; !ok {
+			unregistered = append(unregistered, name)
+		}
+	}
+	sort.Strings(unregistered)
+
+	if len(unregistered) > 0 {
+		return &UnregisteredVariableError{Expression: expression, Variables: unregistered}
+	}
+
+	return nil
+}
+
+// checkParameters fails with an InvalidParameterError on the first
+// parameter, sorted by name, not allowed by its schema.
+func checkParameters(schemas map[string]repositories.Variable, parameters map[string]int) error {
+	names := []string{}
+	for name := range schemas {
+		if _, ok := parameters[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := schemas[name]
+		if reason := checkVariableValue(&v, parameters[name]); reason != "" {
+			return &InvalidParameterError{Parameter: name, Value: parameters[name], Reason: reason}
+		}
+	}
+
+	return nil
+}
+
+type VariableServiceOption func(vs *variableService)
+
+func NewVariableService(options ...VariableServiceOption) VariableService {
+	vs := &variableService{}
+
+	for _, option := range options {
+		option(vs)
+	}
+
+	return vs
+}
+
+var _ VariableService = (*variableService)(nil)
+
+func WithVariableRepositoryOption(vr repositories.VariableRepository) VariableServiceOption {
+	return func(vs *variableService) {
+		vs.variableRepository = vr
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CaioTeixeira95/logic-exp/pkg/logic"
+	"github.com/CaioTeixeira95/logic-exp/pkg/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestValidateVariable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		variable repositories.Variable
+		err      string
+	}{
+		{
+			name:     "boolean",
+			variable: repositories.Variable{Name: "active", Type: VariableTypeBoolean, Default: intPointer(1)},
+		},
+		{
+			name:     "bounded integer",
+			variable: repositories.Variable{Name: "age", Type: VariableTypeInteger, Min: intPointer(0), Max: intPointer(130)},
+		},
+		{
+			name:     "enum",
+			variable: repositories.Variable{Name: "tier", Type: VariableTypeEnum, Enum: []int{1, 2, 3}, Default: intPointer(2)},
+		},
+		{
+			name:     "invalid name",
+			variable: repositories.Variable{Name: "Age", Type: VariableTypeInteger},
+			err:      `invalid variable: name "Age" must be made of lowercase letters only`,
+		},
+		{
+			name:     "keyword",
+			variable: repositories.Variable{Name: "true", Type: VariableTypeBoolean},
+			err:      `invalid variable: name "true" is a keyword`,
+		},
+		{
+			name:     "unknown type",
+			variable: repositories.Variable{Name: "age", Type: "float"},
+			err:      `invalid variable: unknown type "float"`,
+		},
+		{
+			name:     "boolean with range",
+			variable: repositories.Variable{Name: "active", Type: VariableTypeBoolean, Max: intPointer(1)},
+			err:      "invalid variable: boolean variables can't have a range or values",
+		},
+		{
+			name:     "empty range",
+			variable: repositories.Variable{Name: "age", Type: VariableTypeInteger, Min: intPointer(10), Max: intPointer(1)},
+			err:      "invalid variable: min 10 is greater than max 1",
+		},
+		{
+			name:     "enum without values",
+			variable: repositories.Variable{Name: "tier", Type: VariableTypeEnum},
+			err:      "invalid variable: enum variables need at least one value",
+		},
+		{
+			name:     "repeated enum value",
+			variable: repositories.Variable{Name: "tier", Type: VariableTypeEnum, Enum: []int{1, 2, 1}},
+			err:      "invalid variable: value 1 is repeated",
+		},
+		{
+			name:     "default out of range",
+			variable: repositories.Variable{Name: "age", Type: VariableTypeInteger, Max: intPointer(130), Default: intPointer(200)},
+			err:      "invalid variable: default 200 is greater than the maximum 130",
+		},
+		{
+			name:     "default not in enum",
+			variable: repositories.Variable{Name: "tier", Type: VariableTypeEnum, Enum: []int{1, 2}, Default: intPointer(3)},
+			err:      "invalid variable: default 3 is not one of 1, 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateVariable(&tc.variable)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				assert.ErrorIs(t, err, ErrInvalidVariable)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVariableService_CreateVariable(t *testing.T) {
+	variableRepositoryMock := &repositories.VariableRepositoryMock{}
+	variableService := NewVariableService(WithVariableRepositoryOption(variableRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the variable is invalid", func(t *testing.T) {
+		v, err := variableService.CreateVariable(ctx, &repositories.Variable{Name: "x", Type: "text"})
+
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+		assert.Nil(t, v)
+	})
+
+	t.Run("returns error when the variable already exists", func(t *testing.T) {
+		v := &repositories.Variable{Name: "x", Type: VariableTypeBoolean}
+
+		variableRepositoryMock.
+			On("CreateVariable", ctx, v).
+			Return(nil, repositories.ErrVariableAlreadyExists).
+			Once()
+
+		_, err := variableService.CreateVariable(ctx, v)
+
+		assert.Equal(t, repositories.ErrVariableAlreadyExists, err)
+	})
+
+	t.Run("creates the variable successfully", func(t *testing.T) {
+		v := &repositories.Variable{Name: "x", Type: VariableTypeBoolean}
+
+		variableRepositoryMock.
+			On("CreateVariable", ctx, v).
+			Return(v, nil).
+			Once()
+
+		got, err := variableService.CreateVariable(ctx, v)
+		require.NoError(t, err)
+
+		assert.Equal(t, v, got)
+	})
+
+	variableRepositoryMock.AssertExpectations(t)
+}
+
+func TestVariableService_UpdateVariable(t *testing.T) {
+	variableRepositoryMock := &repositories.VariableRepositoryMock{}
+	variableService := NewVariableService(WithVariableRepositoryOption(variableRepositoryMock))
+
+	ctx := context.Background()
+
+	t.Run("returns error when the variable is not found", func(t *testing.T) {
+		v := &repositories.Variable{Name: "x", Type: VariableTypeBoolean}
+
+		variableRepositoryMock.
+			On("UpdateVariable", ctx, v).
+			Return(nil, repositories.ErrVariableNotFound).
+			Once()
+
+		_, err := variableService.UpdateVariable(ctx, v)
+
+		assert.Equal(t, repositories.ErrVariableNotFound, err)
+	})
+
+	t.Run("returns error when repository fails", func(t *testing.T) {
+		v := &repositories.Variable{Name: "x", Type: VariableTypeBoolean}
+
+		variableRepositoryMock.
+			On("UpdateVariable", ctx, v).
+			Return(nil, errors.New("unexpected error")).
+			Once()
+
+		_, err := variableService.UpdateVariable(ctx, v)
+
+		assert.EqualError(t, err, "error updating variable: unexpected error")
+	})
+
+	variableRepositoryMock.AssertExpectations(t)
+}
+
+func TestVariableService_DeleteVariable(t *testing.T) {
+	variableRepositoryMock := &repositories.VariableRepositoryMock{}
+	variableService := NewVariableService(WithVariableRepositoryOption(variableRepositoryMock))
+
+	ctx := context.Background()
+
+	variableRepositoryMock.On("DeleteVariable", ctx, "x").Return(nil).Once()
+	variableRepositoryMock.On("DeleteVariable", ctx, "y").Return(repositories.ErrVariableNotFound).Once()
+
+	assert.NoError(t, variableService.DeleteVariable(ctx, "x"))
+	assert.Equal(t, repositories.ErrVariableNotFound, variableService.DeleteVariable(ctx, "y"))
+
+	variableRepositoryMock.AssertExpectations(t)
+}
+
+func TestExpressionService_CreateExpression_RegisteredVariables(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects unregistered variables", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		variableRepositoryMock := &repositories.VariableRepositoryMock{}
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithVariableRegistryOption(variableRepositoryMock),
+		)
+
+		variableRepositoryMock.
+			On("GetVariablesByName", ctx, []string{"x", "y", "z"}).
+			Return([]repositories.Variable{{Name: "y", Type: VariableTypeBoolean}}, nil).
+			Once()
+
+		_, err := expressionService.CreateExpression(ctx, &repositories.Expression{Value: "x AND y OR z"}, WithRegisteredVariablesOption())
+
+		var unregisteredErr *UnregisteredVariableError
+		require.ErrorAs(t, err, &unregisteredErr)
+		assert.Equal(t, []string{"x", "z"}, unregisteredErr.Variables)
+		assert.EqualError(t, err, `unregistered variables "x", "z" in the logical expression "x AND y OR z"`)
+
+		expressionRepositoryMock.AssertNotCalled(t, "CreateExpression", mock.Anything, mock.Anything)
+		variableRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("creates expressions using registered variables", func(t *testing.T) {
+		expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+		variableRepositoryMock := &repositories.VariableRepositoryMock{}
+		expressionService := NewExpressionService(
+			WithExpressionRepositoryOption(expressionRepositoryMock),
+			WithVariableRegistryOption(variableRepositoryMock),
+		)
+
+		exp := &repositories.Expression{Value: "x AND y"}
+
+		variableRepositoryMock.
+			On("GetVariablesByName", ctx, []string{"x", "y"}).
+			Return([]repositories.Variable{{Name: "x", Type: VariableTypeBoolean}, {Name: "y", Type: VariableTypeBoolean}}, nil).
+			Once()
+		expressionRepositoryMock.
+			On("CreateExpression", ctx, exp).
+			Return(&repositories.Expression{ID: 1, Value: "x AND y"}, nil).
+			Once()
+
+		got, err := expressionService.CreateExpression(ctx, exp, WithRegisteredVariablesOption())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), got.ID)
+
+		expressionRepositoryMock.AssertExpectations(t)
+		variableRepositoryMock.AssertExpectations(t)
+	})
+
+	t.Run("rejects every variable without a variable registry", func(t *testing.T) {
+		expressionService := NewExpressionService(WithExpressionRepositoryOption(&repositories.ExpressionRepositoryMock{}))
+
+		_, err := expressionService.CreateExpression(ctx, &repositories.Expression{Value: "x OR y"}, WithRegisteredVariablesOption())
+
+		var unregisteredErr *UnregisteredVariableError
+		require.ErrorAs(t, err, &unregisteredErr)
+		assert.Equal(t, []string{"x", "y"}, unregisteredErr.Variables)
+	})
+}
+
+func TestExpressionService_EvaluateExpressionWithOptions_Schema(t *testing.T) {
+	ctx := context.Background()
+
+	schemas := []repositories.Variable{
+		{Name: "age", Type: VariableTypeInteger, Min: intPointer(0), Max: intPointer(130)},
+		{Name: "tier", Type: VariableTypeEnum, Enum: []int{0, 1, 2}, Default: intPointer(2)},
+	}
+
+	testCases := []struct {
+		name       string
+		parameters map[string]int
+		opts       EvaluationOptions
+		expect     logic.Truth
+		err        string
+	}{
+		{
+			name:       "allowed values",
+			parameters: map[string]int{"age": 30, "tier": 1, "x": 0},
+			expect:     logic.True,
+		},
+		{
+			name:       "value out of range",
+			parameters: map[string]int{"age": -1, "tier": 1, "x": 0},
+			expect:     logic.Unknown,
+			err:        `invalid value -1 for the parameter "age": is less than the minimum 0`,
+		},
+		{
+			name:       "value not in enum",
+			parameters: map[string]int{"age": 30, "tier": 5, "x": 0},
+			expect:     logic.Unknown,
+			err:        `invalid value 5 for the parameter "tier": is not one of 0, 1, 2`,
+		},
+		{
+			name:       "unregistered variables are not validated",
+			parameters: map[string]int{"age": 0, "tier": 0, "x": 42},
+			expect:     logic.True,
+		},
+		{
+			name:       "registered defaults take precedence",
+			parameters: map[string]int{"age": 30},
+			opts:       EvaluationOptions{MissingParameters: MissingParametersDefault},
+			expect:     logic.True,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expressionRepositoryMock := &repositories.ExpressionRepositoryMock{}
+			variableRepositoryMock := &repositories.VariableRepositoryMock{}
+			expressionService := NewExpressionService(
+				WithExpressionRepositoryOption(expressionRepositoryMock),
+				WithVariableRegistryOption(variableRepositoryMock),
+			)
+
+			expressionRepositoryMock.
+				On("GetExpressionByID", ctx, int64(1)).
+				Return(&repositories.Expression{ID: 1, Value: "age AND tier OR x"}, nil).
+				Once()
+			variableRepositoryMock.
+				On("GetVariablesByName", ctx, []string{"age", "tier", "x"}).
+				Return(schemas, nil).
+				Once()
+
+			res, err := expressionService.EvaluateExpressionWithOptions(ctx, 1, tc.parameters, tc.opts)
+			if tc.err != "" {
+				var invalidErr *InvalidParameterError
+				assert.ErrorAs(t, err, &invalidErr)
+				assert.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expect, res)
+		})
+	}
+}
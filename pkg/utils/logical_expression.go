@@ -53,6 +53,9 @@ func IsLogicalExpressionValid(logicalExpression string) bool {
 func EvaluateLogicalExpression(logicalExpression string, parameters map[string]int) (bool, error) {
 	logicalExpression = strings.ReplaceAll(logicalExpression, "AND", "&&")
 	logicalExpression = strings.ReplaceAll(logicalExpression, "OR", "||")
+	logicalExpression = strings.ReplaceAll(logicalExpression, "NOT", "!")
+	logicalExpression = strings.ReplaceAll(logicalExpression, "TRUE", "true")
+	logicalExpression = strings.ReplaceAll(logicalExpression, "FALSE", "false")
 
 	exp, err := govaluate.NewEvaluableExpression(logicalExpression)
 	if err != nil {
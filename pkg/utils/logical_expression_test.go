@@ -104,6 +104,10 @@ func TestIsLogicalExpressionValid(t *testing.T) {
 			expression: "(x OR )",
 			expect:     false,
 		},
+		{
+			expression: "NOT x AND TRUE",
+			expect:     true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -178,6 +182,15 @@ func TestEvaluateExpression(t *testing.T) {
 			expect: true,
 			err:    "",
 		},
+		{
+			expression: "NOT x AND (y OR FALSE)",
+			parameters: map[string]int{
+				"x": 0,
+				"y": 1,
+			},
+			expect: true,
+			err:    "",
+		},
 		// Expressions with error
 		{
 			expression: "x AND",